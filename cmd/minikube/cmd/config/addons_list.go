@@ -17,22 +17,42 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"text/template"
 
+	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/minikube/pkg/minikube/addons"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
 )
 
-var addonListFormat string
+var (
+	addonListFormat string
+	addonListOutput string
+)
 
 type AddonListTemplate struct {
-	AddonName   string
-	AddonStatus string
+	AddonName   string `json:"name"`
+	AddonStatus string `json:"status"`
+	// AddonReason explains why an enabled addon is enabled, if it's required
+	// by other enabled addons.
+	AddonReason string `json:"reason,omitempty"`
+	// AddonHealth is the state of the workloads run by an enabled addon:
+	// Running, Pending, Failing or Not deployed. It is empty if the addon
+	// doesn't run any workloads or the cluster isn't running.
+	AddonHealth       string `json:"health,omitempty"`
+	AddonHealthReason string `json:"healthReason,omitempty"`
 }
 
 var addonsListCmd = &cobra.Command{
@@ -56,6 +76,7 @@ func init() {
 	AddonsCmd.Flags().StringVar(&addonListFormat, "format", constants.DefaultAddonListFormat,
 		`Go template format string for the addon list output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
 For the list of accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd/config#AddonListTemplate`)
+	addonsListCmd.Flags().StringVarP(&addonListOutput, "output", "o", "", "Output format, either empty to use the --format template, or json")
 	AddonsCmd.AddCommand(addonsListCmd)
 }
 
@@ -73,23 +94,79 @@ func addonList() error {
 	}
 	sort.Strings(addonNames)
 
+	client := addonHealthClient()
+	var list []AddonListTemplate
 	for _, addonName := range addonNames {
 		addonBundle := assets.Addons[addonName]
 		addonStatus, err := addonBundle.IsEnabled()
 		if err != nil {
 			return err
 		}
+		listTmplt := AddonListTemplate{AddonName: addonName, AddonStatus: stringFromStatus(addonStatus)}
+		if addonStatus {
+			dependents, err := assets.EnabledAddons(assets.RequiredBy(addonName))
+			if err != nil {
+				return err
+			}
+			if len(dependents) > 0 {
+				listTmplt.AddonReason = "required by " + strings.Join(dependents, ", ")
+			}
+			if client != nil {
+				health, ok, err := addons.CheckHealth(client, addonBundle)
+				if err != nil {
+					glog.Warningf("Error checking health of addon %s: %s", addonName, err)
+				} else if ok {
+					listTmplt.AddonHealth = string(health.Status)
+					listTmplt.AddonHealthReason = health.Reason
+				}
+			}
+		}
+		list = append(list, listTmplt)
+	}
+
+	switch addonListOutput {
+	case "":
 		tmpl, err := template.New("list").Parse(addonListFormat)
 		if err != nil {
 			glog.Errorln("Error creating list template:", err)
 			os.Exit(1)
 		}
-		listTmplt := AddonListTemplate{addonName, stringFromStatus(addonStatus)}
-		err = tmpl.Execute(os.Stdout, listTmplt)
+		for _, listTmplt := range list {
+			err = tmpl.Execute(os.Stdout, listTmplt)
+			if err != nil {
+				glog.Errorln("Error executing list template:", err)
+				os.Exit(1)
+			}
+		}
+	case "json":
+		b, err := json.MarshalIndent(list, "", "    ")
 		if err != nil {
-			glog.Errorln("Error executing list template:", err)
-			os.Exit(1)
+			return errors.Wrap(err, "encoding addon list")
 		}
+		fmt.Fprintln(os.Stdout, string(b))
+	default:
+		return errors.Errorf("invalid output format %q, must be empty or json", addonListOutput)
 	}
 	return nil
 }
+
+// addonHealthClient returns a client for querying addon health, or nil if
+// the cluster isn't running.
+func addonHealthClient() corev1.PodsGetter {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		glog.Warningf("Error getting client: %s", err)
+		return nil
+	}
+	defer api.Close()
+	status, err := cluster.GetHostStatus(api)
+	if err != nil || status != state.Running.String() {
+		return nil
+	}
+	client, err := service.K8s.GetCoreClient()
+	if err != nil {
+		glog.Warningf("Error getting kubernetes client: %s", err)
+		return nil
+	}
+	return client
+}
@@ -59,13 +59,13 @@ var settings = []Setting{
 		name:        "cpus",
 		set:         SetInt,
 		validations: []setFn{IsPositive},
-		callbacks:   []setFn{RequiresRestartMsg},
+		callbacks:   []setFn{ResourceChangeMsg},
 	},
 	{
 		name:        "disk-size",
 		set:         SetString,
 		validations: []setFn{IsValidDiskSize},
-		callbacks:   []setFn{RequiresRestartMsg},
+		callbacks:   []setFn{DiskResizeMsg},
 	},
 	{
 		name:        "host-only-cidr",
@@ -76,7 +76,7 @@ var settings = []Setting{
 		name:        "memory",
 		set:         SetInt,
 		validations: []setFn{IsPositive},
-		callbacks:   []setFn{RequiresRestartMsg},
+		callbacks:   []setFn{ResourceChangeMsg},
 	},
 	{
 		name:        "log_dir",
@@ -131,85 +131,85 @@ var settings = []Setting{
 	{
 		name:        "dashboard",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "addon-manager",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "default-storageclass",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "coredns",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "kube-dns",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "heapster",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "efk",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "ingress",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "registry",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "registry-creds",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "freshpod",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "default-storageclass",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableDefaultStorageClass},
 	},
 	{
 		name:        "storage-provisioner",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "metrics-server",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
@@ -312,6 +312,29 @@ func DeleteFromConfigMap(name string, images []string) error {
 	return WriteConfig(configFile)
 }
 
+// SetAddonParameters merges the given parameters into those stored in the
+// config file for an addon
+func SetAddonParameters(addonName string, params map[string]string) error {
+	configFile, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+	addons, ok := configFile[config.AddonParameters].(map[string]interface{})
+	if !ok {
+		addons = make(map[string]interface{})
+	}
+	values, ok := addons[addonName].(map[string]interface{})
+	if !ok {
+		values = make(map[string]interface{})
+	}
+	for k, v := range params {
+		values[k] = v
+	}
+	addons[addonName] = values
+	configFile[config.AddonParameters] = addons
+	return WriteConfig(configFile)
+}
+
 // WriteConfig writes a minikube config to the JSON file
 func WriteConfig(m config.MinikubeConfig) error {
 	f, err := os.Create(constants.ConfigFile)
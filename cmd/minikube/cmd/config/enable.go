@@ -19,8 +19,16 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+var (
+	addonParameters  []string
+	disableConflicts bool
 )
 
 var addonsEnableCmd = &cobra.Command{
@@ -34,7 +42,13 @@ var addonsEnableCmd = &cobra.Command{
 		}
 
 		addon := args[0]
-		err := Set(addon, "true")
+		if len(addonParameters) > 0 {
+			if err := setAddonParameters(addon, addonParameters); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+		err := enableAddon(addon, disableConflicts)
 		if err != nil {
 			fmt.Fprintln(os.Stdout, err)
 		} else {
@@ -43,6 +57,93 @@ var addonsEnableCmd = &cobra.Command{
 	},
 }
 
+// enableAddon enables an addon along with any addons it requires. Enabled
+// addons that conflict with it are disabled if disableConflicts is set,
+// otherwise enabling fails.
+func enableAddon(name string, disableConflicts bool) error {
+	if err := IsValidAddon(name, "true"); err != nil {
+		return err
+	}
+	required, err := assets.RequiredAddons(name)
+	if err != nil {
+		return err
+	}
+	if disableConflicts {
+		for _, n := range append(required, name) {
+			conflicts, err := assets.EnabledAddons(assets.ConflictingAddons(n))
+			if err != nil {
+				return err
+			}
+			for _, c := range conflicts {
+				fmt.Fprintf(os.Stdout, "Disabling %s, it conflicts with %s\n", c, n)
+				if err := Set(c, "false"); err != nil {
+					return errors.Wrapf(err, "disabling conflicting addon %s", c)
+				}
+			}
+		}
+	}
+	toEnable, err := disabledAddons(required)
+	if err != nil {
+		return err
+	}
+	for _, r := range toEnable {
+		fmt.Fprintf(os.Stdout, "Enabling %s, it is required by %s\n", r, name)
+		if err := Set(r, "true"); err != nil {
+			return errors.Wrapf(err, "enabling required addon %s", r)
+		}
+	}
+	return Set(name, "true")
+}
+
+func disabledAddons(names []string) ([]string, error) {
+	enabled, err := assets.EnabledAddons(names)
+	if err != nil {
+		return nil, err
+	}
+	isEnabled := map[string]bool{}
+	for _, n := range enabled {
+		isEnabled[n] = true
+	}
+	var disabled []string
+	for _, n := range names {
+		if !isEnabled[n] {
+			disabled = append(disabled, n)
+		}
+	}
+	return disabled, nil
+}
+
+// setAddonParameters validates key=value parameters for an addon and
+// persists them in the minikube config.
+func setAddonParameters(addonName string, values []string) error {
+	addon, ok := assets.Addons[addonName]
+	if !ok {
+		return errors.Errorf("Cannot set parameters for invalid addon %s", addonName)
+	}
+	params, err := parseAddonParameters(values)
+	if err != nil {
+		return err
+	}
+	if err := addon.ValidateParameters(params); err != nil {
+		return err
+	}
+	return SetAddonParameters(addonName, params)
+}
+
+func parseAddonParameters(values []string) (map[string]string, error) {
+	params := make(map[string]string)
+	for _, v := range values {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("Invalid addon parameter %q, must be of the form key=value", v)
+		}
+		params[kv[0]] = kv[1]
+	}
+	return params, nil
+}
+
 func init() {
+	addonsEnableCmd.Flags().StringArrayVar(&addonParameters, "set", nil, "Set an addon parameter (key=value). May be repeated. Parameters are persisted in the minikube config.")
+	addonsEnableCmd.Flags().BoolVar(&disableConflicts, "disable-conflicts", false, "Disable any enabled addons that conflict with the addon being enabled.")
 	AddonsCmd.AddCommand(addonsEnableCmd)
 }
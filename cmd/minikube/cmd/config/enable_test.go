@@ -16,10 +16,53 @@ limitations under the License.
 
 package config
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestEnableUnknownAddon(t *testing.T) {
 	if err := Set("InvalidAddon", "false"); err == nil {
 		t.Fatalf("Enable did not return error for unknown addon")
 	}
 }
+
+func TestParseAddonParameters(t *testing.T) {
+	var testcases = []struct {
+		values   []string
+		expected map[string]string
+		err      bool
+	}{
+		{
+			values:   []string{"image=example.com/registry:2", "storageSize=20Gi"},
+			expected: map[string]string{"image": "example.com/registry:2", "storageSize": "20Gi"},
+		},
+		{
+			values:   []string{"extraArgs=--enable-insecure-login,--token-ttl=0"},
+			expected: map[string]string{"extraArgs": "--enable-insecure-login,--token-ttl=0"},
+		},
+		{
+			values: []string{"image"},
+			err:    true,
+		},
+		{
+			values: []string{"=value"},
+			err:    true,
+		},
+	}
+
+	for _, tt := range testcases {
+		params, err := parseAddonParameters(tt.values)
+		if err != nil && !tt.err {
+			t.Errorf("Unexpected error parsing %v: %v", tt.values, err)
+			continue
+		}
+		if err == nil && tt.err {
+			t.Errorf("Expected error parsing %v, got none", tt.values)
+			continue
+		}
+		if !tt.err && !reflect.DeepEqual(params, tt.expected) {
+			t.Errorf("Expected %v, got %v", tt.expected, params)
+		}
+	}
+}
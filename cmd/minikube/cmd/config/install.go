@@ -0,0 +1,52 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+var addonsInstallCmd = &cobra.Command{
+	Use:   "install PATH_OR_URL",
+	Short: "Installs an addon package from a directory, tarball or git repository",
+	Long: `Installs an addon package from a local directory, a local or remote tarball (.tar, .tar.gz, .tgz) or a git repository.
+The package must contain an ` + assets.AddonManifestFile + ` manifest at its root. Once installed, the addon can be enabled and disabled like the built-in addons.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube addons install PATH_OR_URL")
+			os.Exit(1)
+		}
+
+		addon, err := assets.InstallAddonPackage(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stdout, fmt.Sprintf("%s was successfully installed", addon.Name()))
+		if enabled, err := addon.IsEnabled(); err == nil && enabled {
+			fmt.Fprintln(os.Stdout, fmt.Sprintf("%s is enabled by default and will be deployed on the next minikube start, or run: minikube addons enable %s", addon.Name(), addon.Name()))
+		}
+	},
+}
+
+func init() {
+	AddonsCmd.AddCommand(addonsInstallCmd)
+}
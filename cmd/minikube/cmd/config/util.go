@@ -22,11 +22,16 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/addons"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
 	"k8s.io/minikube/pkg/minikube/storageclass"
 )
 
@@ -51,6 +56,15 @@ func findSetting(name string) (Setting, error) {
 			return s, nil
 		}
 	}
+	// Installed addon packages don't have a static entry in settings
+	if _, ok := assets.Addons[name]; ok {
+		return Setting{
+			name:        name,
+			set:         SetBool,
+			validations: []setFn{IsValidAddon, IsAddonCompatible},
+			callbacks:   []setFn{EnableOrDisableAddon},
+		}, nil
+	}
 	return Setting{}, fmt.Errorf("Property name %s not found", name)
 }
 
@@ -98,7 +112,7 @@ func EnableOrDisableAddon(name string, val string) error {
 
 	enable, err := strconv.ParseBool(val)
 	if err != nil {
-		errors.Wrapf(err, "error attempted to parse enabled/disable value addon %s", name)
+		return errors.Wrapf(err, "error attempted to parse enabled/disable value addon %s", name)
 	}
 
 	//TODO(r2d4): config package should not reference API, pull this out
@@ -108,12 +122,23 @@ func EnableOrDisableAddon(name string, val string) error {
 		os.Exit(1)
 	}
 	defer api.Close()
-	cluster.EnsureMinikubeRunningOrExit(api, 0)
 
 	addon, _ := assets.Addons[name] // validation done prior
+	status, err := cluster.GetHostStatus(api)
 	if err != nil {
-		return err
+		return errors.Wrap(err, "getting host status")
+	}
+	if status != state.Running.String() {
+		// The change is persisted in the config, apply it on the next start
+		if status != state.None.String() {
+			if err := addons.QueueChange(config.GetMachineName(), name, enable); err != nil {
+				return errors.Wrap(err, "queueing addon change")
+			}
+		}
+		fmt.Fprintf(os.Stdout, "minikube is not running, the change to %s will be applied on the next minikube start\n", name)
+		return nil
 	}
+
 	host, err := cluster.CheckIfApiExistsAndLoad(api)
 	if err != nil {
 		return errors.Wrap(err, "getting host")
@@ -123,21 +148,94 @@ func EnableOrDisableAddon(name string, val string) error {
 		return errors.Wrap(err, "getting command runner")
 	}
 	if enable {
-		for _, addon := range addon.Assets {
-			if err := cmd.Copy(addon); err != nil {
-				return errors.Wrapf(err, "error enabling addon %s", addon.AssetName)
+		if cc, err := config.Load(config.GetMachineName()); err == nil {
+			if !addon.SupportsKubernetesVersion(cc.KubernetesConfig.KubernetesVersion) {
+				return errors.Errorf("addon %s requires kubernetes version %s, cluster is running %s",
+					name, addon.KubernetesVersion, cc.KubernetesConfig.KubernetesVersion)
+			}
+		}
+		files, err := addon.RenderAssets()
+		if err != nil {
+			return errors.Wrapf(err, "error enabling addon %s", name)
+		}
+		for _, f := range files {
+			if err := cmd.Copy(f); err != nil {
+				return errors.Wrapf(err, "error enabling addon %s", f.GetAssetName())
 			}
 		}
 	} else {
 		for _, addon := range addon.Assets {
 			if err := cmd.Remove(addon); err != nil {
-				return errors.Wrapf(err, "error disabling addon %s", addon.AssetName)
+				return errors.Wrapf(err, "error disabling addon %s", addon.GetAssetName())
 			}
 		}
 	}
+	ApplyAddonDirectly(addon, enable, true)
 	return nil
 }
 
+// ApplyAddonDirectly applies or deletes an addon's objects through the
+// apiserver, and optionally waits for its pods to be ready. Failures are
+// reported but not returned, as the addon-manager will still reconcile the
+// addons directory in the VM.
+func ApplyAddonDirectly(addon *assets.Addon, enable, wait bool) {
+	restConfig, err := service.GetRESTConfig()
+	if err != nil {
+		glog.Warningf("Unable to apply addon %s directly, relying on the addon-manager: %s", addon.Name(), err)
+		return
+	}
+	applier, err := addons.NewApplier(restConfig)
+	if err != nil {
+		glog.Warningf("Unable to apply addon %s directly, relying on the addon-manager: %s", addon.Name(), err)
+		return
+	}
+	if !enable {
+		if err := applier.Delete(addon); err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting addon %s: %s\n", addon.Name(), err)
+		}
+		return
+	}
+	if err := applier.Apply(addon); err != nil {
+		fmt.Fprintf(os.Stderr, "Error applying addon %s: %s\n", addon.Name(), err)
+		return
+	}
+	if !wait {
+		return
+	}
+	fmt.Fprintf(os.Stdout, "Waiting for %s to be ready...\n", addon.Name())
+	if err := applier.WaitForReady(addon, addons.DefaultReadyTimeout); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+	}
+}
+
+// ApplyPendingAddonChanges applies the addon changes queued while the VM was
+// stopped. It is called by start once the cluster is up.
+func ApplyPendingAddonChanges(runner bootstrapper.CommandRunner) error {
+	profile := config.GetMachineName()
+	changes, err := addons.PendingChanges(profile)
+	if err != nil {
+		return err
+	}
+	for name, enable := range changes {
+		addon, ok := assets.Addons[name]
+		if !ok {
+			glog.Warningf("Skipping queued change for unknown addon %s", name)
+			continue
+		}
+		// Enabled addons are copied into the VM when the cluster is updated,
+		// but disabled ones have to be removed
+		if !enable {
+			for _, f := range addon.Assets {
+				if err := runner.Remove(f); err != nil {
+					glog.Warningf("Error removing %s: %s", f.GetAssetName(), err)
+				}
+			}
+		}
+		ApplyAddonDirectly(addon, enable, false)
+	}
+	return addons.ClearPendingChanges(profile)
+}
+
 func EnableOrDisableDefaultStorageClass(name, val string) error {
 	enable, err := strconv.ParseBool(val)
 	if err != nil {
@@ -22,11 +22,13 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 
 	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/registry"
 )
 
 func IsValidDriver(string, driver string) error {
@@ -35,6 +37,10 @@ func IsValidDriver(string, driver string) error {
 			return nil
 		}
 	}
+	// Discovered driver plugins are registered too
+	if _, err := registry.Driver(driver); err == nil {
+		return nil
+	}
 	return fmt.Errorf("Driver %s is not supported", driver)
 }
 
@@ -43,6 +49,16 @@ func RequiresRestartMsg(string, string) error {
 	return nil
 }
 
+func ResourceChangeMsg(string, string) error {
+	fmt.Fprintln(os.Stdout, "With the kvm2 and hyperkit drivers these changes will take effect upon a minikube stop and then a minikube start, other drivers require a minikube delete and then a minikube start")
+	return nil
+}
+
+func DiskResizeMsg(string, string) error {
+	fmt.Fprintln(os.Stdout, "With the kvm2 driver the disk will be grown upon a minikube stop and then a minikube start, other drivers require a minikube delete and then a minikube start")
+	return nil
+}
+
 func IsValidDiskSize(name string, disksize string) error {
 	_, err := units.FromHumanSize(disksize)
 	if err != nil {
@@ -92,3 +108,32 @@ func IsValidAddon(name string, val string) error {
 	}
 	return errors.Errorf("Cannot enable/disable invalid addon %s", name)
 }
+
+// IsAddonCompatible checks that enabling an addon won't conflict with an
+// enabled addon, and that disabling it won't break an enabled addon that
+// requires it
+func IsAddonCompatible(name string, val string) error {
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrapf(err, "parsing enabled value for addon %s", name)
+	}
+	if enable {
+		conflicts, err := assets.EnabledAddons(assets.ConflictingAddons(name))
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return errors.Errorf(`addon %s conflicts with enabled addon(s): %s
+Disable them first, or run: minikube addons enable %s --disable-conflicts`, name, strings.Join(conflicts, ", "), name)
+		}
+		return nil
+	}
+	dependents, err := assets.EnabledAddons(assets.RequiredBy(name))
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		return errors.Errorf("addon %s is required by enabled addon(s): %s", name, strings.Join(dependents, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+// driversCmd represents the drivers command
+var driversCmd = &cobra.Command{
+	Use:   "drivers",
+	Short: "Lists the VM drivers and whether they can be used on this host",
+	Long: `Lists the VM drivers and whether they can be used on this host, with hints on how to fix them.
+When --vm-driver isn't set, minikube start uses the healthy driver with the highest priority.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := printDrivers(os.Stdout, registry.ProbeDrivers()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing drivers: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func printDrivers(out io.Writer, states []registry.DriverState) error {
+	chosen, _ := registry.Choose(states)
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "DRIVER\tPRIORITY\tSTATUS\tMESSAGE")
+	for _, s := range states {
+		name := s.Def.Name
+		if name == chosen.Name {
+			name += " (default)"
+		}
+		msg := ""
+		if s.State.Error != nil {
+			msg = s.State.Error.Error()
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", name, s.Def.Priority, driverStatus(s.State), msg)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	header := false
+	for _, s := range states {
+		if s.State.Fix == "" {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(out, "\nHow to fix:")
+			header = true
+		}
+		fmt.Fprintf(out, "  %s: %s\n", s.Def.Name, s.State.Fix)
+	}
+	return nil
+}
+
+func driverStatus(s registry.State) string {
+	switch {
+	case s.Healthy && s.Error != nil:
+		return "Degraded"
+	case s.Healthy:
+		return "Healthy"
+	case s.Installed:
+		return "Unhealthy"
+	default:
+		return "Not installed"
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(driversCmd)
+}
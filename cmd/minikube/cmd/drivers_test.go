@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+func TestPrintDrivers(t *testing.T) {
+	states := []registry.DriverState{
+		{
+			Def:   registry.DriverDef{Name: "kvm2", Priority: 80},
+			State: registry.State{Installed: true, Error: errors.New("libvirtd is not running"), Fix: "start libvirtd"},
+		},
+		{
+			Def:   registry.DriverDef{Name: "virtualbox", Priority: 60},
+			State: registry.State{Installed: true, Healthy: true},
+		},
+		{
+			Def:   registry.DriverDef{Name: "none"},
+			State: registry.State{Error: errors.New("docker not found"), Fix: "install docker"},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := printDrivers(&b, states); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	out := b.String()
+	for _, expected := range []string{
+		"kvm2  ",
+		"Unhealthy",
+		"libvirtd is not running",
+		"virtualbox (default)",
+		"Healthy",
+		"Not installed",
+		"  kvm2: start libvirtd\n",
+		"  none: install docker\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "virtualbox:") {
+		t.Errorf("Expected no fix for a healthy driver, got:\n%s", out)
+	}
+}
@@ -18,26 +18,40 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
 var (
-	follow bool
+	follow      bool
+	logSince    time.Duration
+	logTail     int
+	logProblems bool
 )
 
+// maxProblemLines is the number of matching lines printed for each problem
+const maxProblemLines = 5
+
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
-	Use:   "logs",
-	Short: "Gets the logs of the running localkube instance, used for debugging minikube, not user code",
-	Long:  `Gets the logs of the running localkube instance, used for debugging minikube, not user code.`,
+	Use:   "logs [component...]",
+	Short: "Gets the logs of the cluster components, used for debugging minikube, not user code",
+	Long: `Gets the logs of the cluster components, used for debugging minikube, not user code.
+With the kubeadm bootstrapper the components are kubelet, apiserver, etcd, scheduler, controller-manager and dns.
+Without any component, the logs of all components are interleaved in timestamp order.
+The audit log of a cluster started with --audit-policy is printed with minikube logs audit, one JSON event per line.
+With --problems, only the lines matching known failures are printed, along with a suggested remedy.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		api, err := machine.NewAPIClient()
 		if err != nil {
@@ -50,7 +64,30 @@ var logsCmd = &cobra.Command{
 			glog.Exitf("Error getting cluster bootstrapper: %s", err)
 		}
 
-		err = clusterBootstrapper.GetClusterLogsTo(follow, os.Stdout)
+		opts := bootstrapper.LogOptions{
+			Components: args,
+			Since:      logSince,
+			Tail:       logTail,
+			Follow:     follow,
+		}
+		if logProblems {
+			if follow {
+				glog.Exitln("--problems can't be used with --follow")
+			}
+			problems, err := bootstrapper.FindProblems(clusterBootstrapper, loadKubernetesConfig(), opts)
+			if len(problems) == 0 && err == nil {
+				fmt.Println("No known problems found in the logs.")
+			}
+			if len(problems) > 0 {
+				printProblems(os.Stdout, problems)
+			}
+			if err != nil {
+				log.Println("Error looking for problems in the logs:", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			return
+		}
+		err = clusterBootstrapper.GetClusterLogsTo(loadKubernetesConfig(), opts, os.Stdout)
 		if err != nil {
 			log.Println("Error getting machine logs:", err)
 			cmdUtil.MaybeReportErrorAndExit(err)
@@ -60,5 +97,59 @@ var logsCmd = &cobra.Command{
 
 func init() {
 	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
+	logsCmd.Flags().DurationVar(&logSince, "since", 0, "Only show entries newer than a relative duration like 5s, 2m or 3h")
+	logsCmd.Flags().IntVar(&logTail, "tail", 0, "Only show the most recent number of entries, all entries if 0")
+	logsCmd.Flags().BoolVar(&logProblems, "problems", false, "Only show the lines matching known problems, grouped by component with a suggested remedy")
 	RootCmd.AddCommand(logsCmd)
 }
+
+// printProblems prints the problems grouped by component, in the order the
+// components first reported a problem. Only the most recent lines of each
+// problem are printed.
+func printProblems(out io.Writer, problems []bootstrapper.ProblemMatch) {
+	var components []string
+	byComponent := map[string]map[*bootstrapper.LogProblem][]string{}
+	for _, m := range problems {
+		if _, ok := byComponent[m.Component]; !ok {
+			components = append(components, m.Component)
+			byComponent[m.Component] = map[*bootstrapper.LogProblem][]string{}
+		}
+		byComponent[m.Component][m.Problem] = append(byComponent[m.Component][m.Problem], m.Line)
+	}
+
+	fmt.Fprintf(out, "Found %d lines matching known problems:\n", len(problems))
+	for _, c := range components {
+		name := c
+		if name == "" {
+			name = "cluster"
+		}
+		fmt.Fprintf(out, "\n%s:\n", name)
+		for _, p := range bootstrapper.LogProblems {
+			lines, ok := byComponent[c][p]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(out, "  %s:\n", p.Name)
+			if len(lines) > maxProblemLines {
+				fmt.Fprintf(out, "    (%d earlier lines omitted)\n", len(lines)-maxProblemLines)
+				lines = lines[len(lines)-maxProblemLines:]
+			}
+			for _, l := range lines {
+				fmt.Fprintf(out, "    %s\n", l)
+			}
+			fmt.Fprintf(out, "  Suggestion: %s\n", p.Advice)
+		}
+	}
+}
+
+// maybePrintProblems prints the known problems found in the recent cluster
+// logs, to help diagnose a cluster which failed to start.
+func maybePrintProblems(b bootstrapper.Bootstrapper, k8s config.KubernetesConfig, since time.Duration) {
+	problems, err := bootstrapper.FindProblems(b, k8s, bootstrapper.LogOptions{Since: since})
+	if err != nil {
+		glog.Warningf("Error looking for problems in the cluster logs: %s", err)
+	}
+	if len(problems) > 0 {
+		printProblems(os.Stderr, problems)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+)
+
+func TestPrintProblems(t *testing.T) {
+	var logs []string
+	for i := 0; i < 7; i++ {
+		logs = append(logs, fmt.Sprintf("[kubelet] Failed to pull image %d", i))
+	}
+	logs = append(logs,
+		"[apiserver] bind: address already in use",
+		"[kubelet] Running with swap on is not supported",
+	)
+	problems := bootstrapper.MatchProblems(strings.NewReader(strings.Join(logs, "\n")))
+
+	var b bytes.Buffer
+	printProblems(&b, problems)
+	out := b.String()
+
+	for _, expected := range []string{
+		"Found 9 lines matching known problems",
+		"kubelet:\n  image pull failure:\n    (2 earlier lines omitted)\n    Failed to pull image 2\n",
+		"Failed to pull image 6\n  Suggestion: An image could not be downloaded",
+		"\napiserver:\n  port in use:\n    bind: address already in use\n",
+		"  swap is on:\n    Running with swap on is not supported\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if strings.Index(out, "kubelet:") > strings.Index(out, "apiserver:") {
+		t.Errorf("Expected components in the order they first reported a problem, got:\n%s", out)
+	}
+	if strings.Contains(out, "Failed to pull image 1\n") {
+		t.Errorf("Expected only the most recent lines of a problem, got:\n%s", out)
+	}
+}
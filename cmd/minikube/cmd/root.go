@@ -33,6 +33,7 @@ import (
 	"github.com/spf13/viper"
 	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/localkube"
@@ -148,6 +149,9 @@ func initConfig() {
 		glog.Warningf("Error reading config file at %s: %s", configPath, err)
 	}
 	setupViper()
+	if err := assets.LoadInstalledAddons(); err != nil {
+		glog.Warningf("Error loading installed addons: %s", err)
+	}
 }
 
 func setupViper() {
@@ -195,3 +199,16 @@ is intended to replace the localkube bootstrapper. To disable this message, run
 
 	return b, nil
 }
+
+// loadKubernetesConfig returns the kubernetes config of the current profile,
+// or the defaults if the profile has no saved config
+func loadKubernetesConfig() config.KubernetesConfig {
+	cc, err := config.Load(config.GetMachineName())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.Warningf("Error loading the cluster config, using the defaults: %s", err)
+		}
+		return config.KubernetesConfig{}
+	}
+	return cc.KubernetesConfig
+}
@@ -31,16 +31,23 @@ import (
 	"github.com/blang/semver"
 	"github.com/docker/machine/libmachine/host"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdutil "k8s.io/minikube/cmd/util"
+	dockerdriver "k8s.io/minikube/pkg/drivers/docker"
+	"k8s.io/minikube/pkg/drivers/qemu"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/localkube"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/cni"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/kubernetes_versions"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/registry"
 	pkgutil "k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/kubeconfig"
 	"k8s.io/minikube/pkg/version"
@@ -59,23 +66,39 @@ const (
 	hostOnlyCIDR          = "host-only-cidr"
 	containerRuntime      = "container-runtime"
 	networkPlugin         = "network-plugin"
+	cniPlugin             = "cni"
 	hypervVirtualSwitch   = "hyperv-virtual-switch"
 	kvmNetwork            = "kvm-network"
+	kvmPrivateNetworkCIDR = "kvm-private-network-cidr"
+	extraDisks            = "extra-disks"
+	kvmDiskFormat         = "kvm-disk-format"
+	nodeImage             = "node-image"
 	keepContext           = "keep-context"
 	createMount           = "mount"
 	featureGates          = "feature-gates"
 	apiServerName         = "apiserver-name"
 	dnsDomain             = "dns-domain"
+	serviceClusterIPRange = "service-cluster-ip-range"
+	podNetworkCIDR        = "pod-network-cidr"
 	mountString           = "mount-string"
 	disableDriverMounts   = "disable-driver-mounts"
 	cacheImages           = "cache-images"
+	extraConfig           = "extra-config"
+	kubeletConfigPatch    = "kubelet-config-patch"
+	kubeProxyConfigPatch  = "kube-proxy-config-patch"
+	auditPolicy           = "audit-policy"
+	admissionConfig       = "admission-config"
 	uuid                  = "uuid"
 )
 
+// maxExtraDisks is the number of free virtio disk names, vdb to vdz
+const maxExtraDisks = 25
+
 var (
 	registryMirror   []string
 	dockerEnv        []string
 	dockerOpt        []string
+	driverOpt        []string
 	insecureRegistry []string
 	apiServerNames   []string
 	apiServerIPs     []net.IP
@@ -92,6 +115,7 @@ assumes you have already installed one of the VM drivers: virtualbox/vmwarefusio
 }
 
 func runStart(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
 	if glog.V(8) {
 		glog.Infoln("Viper configuration:")
 		viper.Debug()
@@ -124,30 +148,121 @@ func runStart(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if n := viper.GetInt(extraDisks); n < 0 || n > maxExtraDisks {
+		glog.Exitf("--%s must be between 0 and %d", extraDisks, maxExtraDisks)
+	}
+	driver, err := selectDriver(exists)
+	if err != nil {
+		glog.Exitf("Error selecting a driver: %s", err)
+	}
+
+	if viper.GetInt(extraDisks) > 0 && driver != "kvm2" {
+		fmt.Printf("--%s is only supported with the kvm2 driver and will be ignored.\n", extraDisks)
+	}
+
+	if f := viper.GetString(kvmDiskFormat); f != "raw" && f != "qcow2" {
+		glog.Exitf("--%s must be raw or qcow2, got %s", kvmDiskFormat, f)
+	}
+
+	selectedCNI := viper.GetString(cniPlugin)
+	if selectedCNI != "" {
+		if clusterBootstrapper != bootstrapper.BootstrapperTypeKubeadm {
+			glog.Exitf("--%s is only supported with the kubeadm bootstrapper", cniPlugin)
+		}
+		if err := cni.Validate(selectedCNI); err != nil {
+			glog.Exitf("Invalid --%s: %s", cniPlugin, err)
+		}
+		// The manifest is deployed again on every start, from any directory
+		if !cni.IsBundled(selectedCNI) {
+			if selectedCNI, err = filepath.Abs(selectedCNI); err != nil {
+				glog.Exitf("Error resolving the CNI manifest path: %s", err)
+			}
+		}
+	}
+	podCIDR := cni.PodCIDR(cfg.KubernetesConfig{CNI: selectedCNI, PodCIDR: viper.GetString(podNetworkCIDR)})
+
+	policy := readAuditPolicy()
+	admission := readConfigFile(admissionConfig)
+	if policy != "" || admission != "" {
+		if clusterBootstrapper != bootstrapper.BootstrapperTypeKubeadm {
+			glog.Exitf("--%s and --%s are only supported with the kubeadm bootstrapper", auditPolicy, admissionConfig)
+		}
+		if err := kubeadm.ValidateAPIServerFiles(cfg.KubernetesConfig{
+			KubernetesVersion: k8sVersion,
+			AuditPolicy:       policy,
+			AdmissionConfig:   admission,
+		}); err != nil {
+			glog.Exitf("Invalid apiserver config: %s", err)
+		}
+	}
+
+	if err := validateExtraOptions(clusterBootstrapper, cfg.KubernetesConfig{
+		KubernetesVersion: k8sVersion,
+		FeatureGates:      viper.GetString(featureGates),
+		CNI:               selectedCNI,
+		ExtraOptions:      extraOptions,
+		AuditPolicy:       policy,
+		AdmissionConfig:   admission,
+	}); err != nil {
+		glog.Exitf("Invalid --%s:\n%s", extraConfig, err)
+	}
+
+	kubeletPatch := readConfigFile(kubeletConfigPatch)
+	kubeProxyPatch := readConfigFile(kubeProxyConfigPatch)
+	if kubeletPatch != "" || kubeProxyPatch != "" {
+		if clusterBootstrapper != bootstrapper.BootstrapperTypeKubeadm {
+			glog.Exitf("--%s and --%s are only supported with the kubeadm bootstrapper", kubeletConfigPatch, kubeProxyConfigPatch)
+		}
+		if err := kubeadm.ValidateConfigPatches(cfg.KubernetesConfig{
+			KubernetesVersion:    k8sVersion,
+			KubeletConfigPatch:   kubeletPatch,
+			KubeProxyConfigPatch: kubeProxyPatch,
+		}); err != nil {
+			glog.Exitf("Invalid config patch: %s", err)
+		}
+	}
+
+	if err := pkgutil.ValidateClusterNetworks(viper.GetString(serviceClusterIPRange), podCIDR); err != nil {
+		glog.Exitf("Invalid cluster networks: %s", err)
+	}
+
 	// Don't verify version for kubeadm bootstrapped clusters
 	if k8sVersion != constants.DefaultKubernetesVersion && clusterBootstrapper != bootstrapper.BootstrapperTypeKubeadm {
 		validateK8sVersion(k8sVersion)
 	}
 
 	config := cfg.MachineConfig{
-		MinikubeISO:         viper.GetString(isoURL),
-		Memory:              viper.GetInt(memory),
-		CPUs:                viper.GetInt(cpus),
-		DiskSize:            diskSizeMB,
-		VMDriver:            viper.GetString(vmDriver),
-		XhyveDiskDriver:     viper.GetString(xhyveDiskDriver),
-		NFSShare:            viper.GetStringSlice(NFSShare),
-		NFSSharesRoot:       viper.GetString(NFSSharesRoot),
-		DockerEnv:           dockerEnv,
-		DockerOpt:           dockerOpt,
-		InsecureRegistry:    insecureRegistry,
-		RegistryMirror:      registryMirror,
-		HostOnlyCIDR:        viper.GetString(hostOnlyCIDR),
-		HypervVirtualSwitch: viper.GetString(hypervVirtualSwitch),
-		KvmNetwork:          viper.GetString(kvmNetwork),
-		Downloader:          pkgutil.DefaultDownloader{},
-		DisableDriverMounts: viper.GetBool(disableDriverMounts),
-		UUID:                viper.GetString(uuid),
+		MinikubeISO:           viper.GetString(isoURL),
+		Memory:                viper.GetInt(memory),
+		CPUs:                  viper.GetInt(cpus),
+		DiskSize:              diskSizeMB,
+		VMDriver:              driver,
+		XhyveDiskDriver:       viper.GetString(xhyveDiskDriver),
+		NFSShare:              viper.GetStringSlice(NFSShare),
+		NFSSharesRoot:         viper.GetString(NFSSharesRoot),
+		DockerEnv:             dockerEnv,
+		DockerOpt:             dockerOpt,
+		DriverOptions:         driverOpt,
+		InsecureRegistry:      insecureRegistry,
+		RegistryMirror:        registryMirror,
+		ServiceCIDR:           viper.GetString(serviceClusterIPRange),
+		HostOnlyCIDR:          viper.GetString(hostOnlyCIDR),
+		HypervVirtualSwitch:   viper.GetString(hypervVirtualSwitch),
+		KvmNetwork:            viper.GetString(kvmNetwork),
+		KvmPrivateNetworkCIDR: viper.GetString(kvmPrivateNetworkCIDR),
+		ExtraDisks:            viper.GetInt(extraDisks),
+		KvmDiskFormat:         viper.GetString(kvmDiskFormat),
+		NodeImage:             viper.GetString(nodeImage),
+		Downloader:            pkgutil.DefaultDownloader{},
+		DisableDriverMounts:   viper.GetBool(disableDriverMounts),
+		UUID:                  viper.GetString(uuid),
+	}
+
+	if err := setKvmPrivateNetwork(&config, exists); err != nil {
+		glog.Exitf("Error setting up the KVM private network: %s", err)
+	}
+	if exists {
+		keepMachineResources(cmd, &config)
 	}
 
 	fmt.Printf("Starting local Kubernetes %s cluster...\n", viper.GetString(kubernetesVersion))
@@ -172,11 +287,25 @@ func runStart(cmd *cobra.Command, args []string) {
 		glog.Errorln("Error getting VM IP address: ", err)
 		cmdutil.MaybeReportErrorAndExit(err)
 	}
+	// The qemu VM is only reachable through ports forwarded to the host, so
+	// the cluster is set up with its guest address and reached through the
+	// host's loopback address.
+	qemuDriver, isQemu := host.Driver.(*qemu.Driver)
+	if isQemu {
+		ip = qemu.GuestIP
+		apiServerIPs = append(apiServerIPs, net.ParseIP("127.0.0.1"))
+	}
+	// The apiserver of the docker node is reached through its port
+	// published on the host's loopback address too
+	dockerDriver, isDocker := host.Driver.(*dockerdriver.Driver)
+	if isDocker {
+		apiServerIPs = append(apiServerIPs, net.ParseIP("127.0.0.1"))
+	}
 
 	selectedKubernetesVersion := viper.GetString(kubernetesVersion)
 
 	// Load profile cluster config from file
-	cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+	cc, err := cfg.Load(viper.GetString(cfg.MachineProfile))
 	if err != nil && !os.IsNotExist(err) {
 		glog.Errorln("Error loading profile config: ", err)
 	}
@@ -196,6 +325,11 @@ func runStart(cmd *cobra.Command, args []string) {
 			selectedKubernetesVersion = version.VersionPrefix + oldKubernetesVersion.String()
 			fmt.Println("Kubernetes version downgrade is not supported. Using version:", selectedKubernetesVersion)
 		}
+		// kubeadm clusters are upgraded in place by minikube upgrade
+		if newKubernetesVersion.GT(oldKubernetesVersion) && clusterBootstrapper == bootstrapper.BootstrapperTypeKubeadm {
+			selectedKubernetesVersion = version.VersionPrefix + oldKubernetesVersion.String()
+			fmt.Printf("Using the cluster's version %s, upgrade it with: minikube upgrade --kubernetes-version=%s\n", selectedKubernetesVersion, viper.GetString(kubernetesVersion))
+		}
 	}
 
 	kubernetesConfig := cfg.KubernetesConfig{
@@ -209,8 +343,14 @@ func runStart(cmd *cobra.Command, args []string) {
 		FeatureGates:           viper.GetString(featureGates),
 		ContainerRuntime:       viper.GetString(containerRuntime),
 		NetworkPlugin:          viper.GetString(networkPlugin),
-		ServiceCIDR:            pkgutil.DefaultServiceCIDR,
+		CNI:                    selectedCNI,
+		ServiceCIDR:            viper.GetString(serviceClusterIPRange),
+		PodCIDR:                podCIDR,
 		ExtraOptions:           extraOptions,
+		KubeletConfigPatch:     kubeletPatch,
+		KubeProxyConfigPatch:   kubeProxyPatch,
+		AuditPolicy:            policy,
+		AdmissionConfig:        admission,
 		ShouldLoadCachedImages: shouldCacheImages,
 	}
 
@@ -248,6 +388,15 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 	kubeHost = strings.Replace(kubeHost, "tcp://", "https://", -1)
 	kubeHost = strings.Replace(kubeHost, ":2376", ":"+strconv.Itoa(pkgutil.APIServerPort), -1)
+	if isQemu {
+		kubeHost = fmt.Sprintf("https://127.0.0.1:%d", qemuDriver.APIServerPort)
+	}
+	if isDocker {
+		if kubeHost, err = dockerDriver.GetAPIServerURL(); err != nil {
+			glog.Errorln("Error getting the apiserver address: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+	}
 
 	fmt.Println("Setting up kubeconfig...")
 	// setup kubeconfig
@@ -274,15 +423,26 @@ func runStart(cmd *cobra.Command, args []string) {
 	if !exists || config.VMDriver == "none" {
 		if err := k8sBootstrapper.StartCluster(kubernetesConfig); err != nil {
 			glog.Errorln("Error starting cluster: ", err)
+			maybePrintProblems(k8sBootstrapper, kubernetesConfig, problemsWindow(startTime))
 			cmdutil.MaybeReportErrorAndExit(err)
 		}
 	} else {
 		if err := k8sBootstrapper.RestartCluster(kubernetesConfig); err != nil {
 			glog.Errorln("Error restarting cluster: ", err)
+			maybePrintProblems(k8sBootstrapper, kubernetesConfig, problemsWindow(startTime))
 			cmdutil.MaybeReportErrorAndExit(err)
 		}
 	}
 
+	runner, err := machine.GetCommandRunner(host)
+	if err != nil {
+		glog.Errorln("Error getting command runner: ", err)
+		cmdutil.MaybeReportErrorAndExit(err)
+	}
+	if err := cmdcfg.ApplyPendingAddonChanges(runner); err != nil {
+		glog.Errorln("Error applying pending addon changes: ", err)
+	}
+
 	// start 9p server mount
 	if viper.GetBool(createMount) {
 		fmt.Printf("Setting up hostmount on %s...\n", viper.GetString(mountString))
@@ -325,19 +485,12 @@ WARNING: IT IS RECOMMENDED NOT TO RUN THE NONE DRIVER ON PERSONAL WORKSTATIONS
 `)
 		}
 
-		if os.Getenv("CHANGE_MINIKUBE_NONE_USER") == "" {
-			fmt.Println(`When using the none driver, the kubectl config and credentials generated will be root owned and will appear in the root home directory.
-You will need to move the files to the appropriate location and then set the correct permissions.  An example of this is below:
-
-	sudo mv /root/.kube $HOME/.kube # this will write over any previous configuration
-	sudo chown -R $USER $HOME/.kube
-	sudo chgrp -R $USER $HOME/.kube
-	
-	sudo mv /root/.minikube $HOME/.minikube # this will write over any previous configuration
-	sudo chown -R $USER $HOME/.minikube
-	sudo chgrp -R $USER $HOME/.minikube 
-
-This can also be done automatically by setting the env var CHANGE_MINIKUBE_NONE_USER=true`)
+		if usr, err := pkgutil.SudoUser(); err != nil {
+			glog.Errorln("Error looking up sudo user: ", err)
+		} else if usr != nil && !strings.HasPrefix(constants.GetMinipath(), usr.HomeDir+string(filepath.Separator)) {
+			fmt.Printf("The minikube files were written to %s, outside of the home directory of %s.\n"+
+				"Run minikube with `sudo -E` to keep them, and the kubectl config, in your home directory.\n",
+				constants.GetMinipath(), usr.Username)
 		}
 		if err := pkgutil.MaybeChownDirRecursiveToMinikubeUser(constants.GetMinipath()); err != nil {
 			glog.Errorf("Error recursively changing ownership of directory %s: %s",
@@ -353,6 +506,192 @@ This can also be done automatically by setting the env var CHANGE_MINIKUBE_NONE_
 	}
 }
 
+// problemsWindow returns how far back to look for problems in the logs of a
+// start which began at startTime, with a margin for the VM's clock drifting
+// from the host's.
+func problemsWindow(startTime time.Time) time.Duration {
+	return time.Since(startTime) + time.Minute
+}
+
+// setKvmPrivateNetwork picks the private network of a KVM machine. New kvm2
+// machines get the first free network that doesn't overlap a host route, such
+// as a VPN, or another profile's network, unless one is given.
+func setKvmPrivateNetwork(config *cfg.MachineConfig, exists bool) error {
+	switch config.VMDriver {
+	case "kvm2":
+	case "kvm":
+		if config.KvmPrivateNetworkCIDR != "" {
+			fmt.Printf("The kvm driver doesn't support --%s, %s will be used. Use the kvm2 driver to change it.\n", kvmPrivateNetworkCIDR, constants.KvmDockerMachinesNetworkCIDR)
+		}
+		config.KvmPrivateNetworkCIDR = constants.KvmDockerMachinesNetworkCIDR
+		if exists {
+			return nil
+		}
+		return checkHostRoutes(config.KvmPrivateNetworkCIDR)
+	default:
+		return nil
+	}
+
+	profile := viper.GetString(cfg.MachineProfile)
+	if exists {
+		// The network of an existing machine can't be changed
+		cc, err := cfg.Load(profile)
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "loading profile config")
+		}
+		existing := cc.MachineConfig.KvmPrivateNetworkCIDR
+		if existing == "" {
+			existing = constants.DefaultKvmPrivateNetworkCIDR
+		}
+		if config.KvmPrivateNetworkCIDR != "" && config.KvmPrivateNetworkCIDR != existing {
+			fmt.Printf("The existing VM uses the private network %s. Delete it with `minikube delete` to change the network.\n", existing)
+		}
+		config.KvmPrivateNetworkCIDR = existing
+		return nil
+	}
+
+	used, err := otherProfileNetworks(profile)
+	if err != nil {
+		return err
+	}
+	if config.KvmPrivateNetworkCIDR != "" {
+		n, err := pkgutil.ParsePrivateNetwork(config.KvmPrivateNetworkCIDR)
+		if err != nil {
+			return err
+		}
+		for p, u := range used {
+			if u.Contains(n.CIDR.IP) || n.CIDR.Contains(u.IP) {
+				return fmt.Errorf("the private network %s overlaps the network %s of profile %s", n.CIDR, u, p)
+			}
+		}
+		return checkHostRoutes(config.KvmPrivateNetworkCIDR)
+	}
+
+	routes, err := pkgutil.HostRoutes()
+	if err != nil {
+		return errors.Wrap(err, "reading host routes")
+	}
+	var taken []*net.IPNet
+	for _, r := range routes {
+		taken = append(taken, r.Destination)
+	}
+	for _, u := range used {
+		taken = append(taken, u)
+	}
+	first, _, err := net.ParseCIDR(constants.DefaultKvmPrivateNetworkCIDR)
+	if err != nil {
+		return err
+	}
+	cidr, err := pkgutil.FreePrivateNetwork(first.String(), taken)
+	if err != nil {
+		return errors.Wrapf(err, "allocating a private network, use --%s to choose one", kvmPrivateNetworkCIDR)
+	}
+	glog.Infof("Using private network %s", cidr)
+	config.KvmPrivateNetworkCIDR = cidr
+	return nil
+}
+
+// otherProfileNetworks returns the private networks of the kvm2 machines of
+// profiles other than the given one, keyed by profile.
+func otherProfileNetworks(profile string) (map[string]*net.IPNet, error) {
+	profiles, err := cfg.ListProfiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing profiles")
+	}
+	networks := map[string]*net.IPNet{}
+	for _, p := range profiles {
+		if p == profile {
+			continue
+		}
+		cc, err := cfg.Load(p)
+		if err != nil {
+			glog.Warningf("Unable to load config for profile %s: %s", p, err)
+			continue
+		}
+		if cc.MachineConfig.VMDriver != "kvm2" {
+			continue
+		}
+		cidr := cc.MachineConfig.KvmPrivateNetworkCIDR
+		if cidr == "" {
+			cidr = constants.DefaultKvmPrivateNetworkCIDR
+		}
+		if _, n, err := net.ParseCIDR(cidr); err == nil {
+			networks[p] = n
+		}
+	}
+	return networks, nil
+}
+
+// checkHostRoutes checks that cidr doesn't overlap an existing host route.
+func checkHostRoutes(cidr string) error {
+	n, err := pkgutil.ParsePrivateNetwork(cidr)
+	if err != nil {
+		return err
+	}
+	route, err := pkgutil.FindOverlappingRoute(n.CIDR)
+	if err != nil {
+		glog.Warningf("Unable to check host routes: %s", err)
+		return nil
+	}
+	if route != nil {
+		return fmt.Errorf("the private network %s overlaps the host route %s. Use --%s to choose another network", cidr, route, kvmPrivateNetworkCIDR)
+	}
+	return nil
+}
+
+// keepMachineResources keeps the cpus and memory of an existing machine unless
+// they were set, so that the machine isn't reconfigured with the defaults.
+func keepMachineResources(cmd *cobra.Command, config *cfg.MachineConfig) {
+	cc, err := cfg.Load(viper.GetString(cfg.MachineProfile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.Errorln("Error loading profile config: ", err)
+		}
+		return
+	}
+	if !isSet(cmd, cpus) && cc.MachineConfig.CPUs != 0 {
+		config.CPUs = cc.MachineConfig.CPUs
+	}
+	if !isSet(cmd, memory) && cc.MachineConfig.Memory != 0 {
+		config.Memory = cc.MachineConfig.Memory
+	}
+}
+
+// isSet returns whether a start flag was set on the command line, in the
+// minikube config or in the environment, rather than left to its default.
+func isSet(cmd *cobra.Command, name string) bool {
+	if cmd.Flags().Changed(name) || viper.InConfig(name) {
+		return true
+	}
+	env := constants.MinikubeEnvPrefix + "_" + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+	_, ok := os.LookupEnv(env)
+	return ok
+}
+
+// selectDriver returns the driver set with --vm-driver or in the minikube
+// config. Otherwise, an existing VM keeps its driver, and a new one gets the
+// healthy driver with the highest priority.
+func selectDriver(exists bool) (string, error) {
+	if d := viper.GetString(vmDriver); d != "" {
+		return d, nil
+	}
+	if exists {
+		cc, err := cfg.Load(viper.GetString(cfg.MachineProfile))
+		if err != nil && !os.IsNotExist(err) {
+			return "", errors.Wrap(err, "loading profile config")
+		}
+		if cc.MachineConfig.VMDriver != "" {
+			return cc.MachineConfig.VMDriver, nil
+		}
+	}
+	def, err := registry.Choose(registry.ProbeDrivers())
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("Using the %s driver, set --%s to use another one.\n", def.Name, vmDriver)
+	return def.Name, nil
+}
+
 func validateK8sVersion(version string) {
 	validVersion, err := kubernetes_versions.IsValidLocalkubeVersion(version, constants.KubernetesVersionGCSURL)
 	if err != nil {
@@ -372,37 +711,89 @@ func init() {
 	startCmd.Flags().String(mountString, constants.DefaultMountDir+":"+constants.DefaultMountEndpoint, "The argument to pass the minikube mount command on start")
 	startCmd.Flags().Bool(disableDriverMounts, false, "Disables the filesystem mounts provided by the hypervisors (vboxfs, xhyve-9p)")
 	startCmd.Flags().String(isoURL, constants.DefaultIsoUrl, "Location of the minikube iso")
-	startCmd.Flags().String(vmDriver, constants.DefaultVMDriver, fmt.Sprintf("VM driver is one of: %v", constants.SupportedVMDrivers))
+	startCmd.Flags().String(vmDriver, "", fmt.Sprintf("VM driver is one of: %v, or a docker-machine driver plugin found in %s or on the PATH. Defaults to the driver of the existing VM, or the best usable driver, see `minikube drivers`", constants.SupportedVMDrivers, constants.MakeMiniPath("bin")))
 	startCmd.Flags().Int(memory, constants.DefaultMemory, "Amount of RAM allocated to the minikube VM in MB")
 	startCmd.Flags().Int(cpus, constants.DefaultCPUS, "Number of CPUs allocated to the minikube VM")
 	startCmd.Flags().String(humanReadableDiskSize, constants.DefaultDiskSize, "Disk size allocated to the minikube VM (format: <number>[<unit>], where unit = b, k, m or g)")
 	startCmd.Flags().String(hostOnlyCIDR, "192.168.99.1/24", "The CIDR to be used for the minikube VM (only supported with Virtualbox driver)")
 	startCmd.Flags().String(hypervVirtualSwitch, "", "The hyperv virtual switch name. Defaults to first found. (only supported with HyperV driver)")
 	startCmd.Flags().String(kvmNetwork, "default", "The KVM network name. (only supported with KVM driver)")
+	startCmd.Flags().String(nodeImage, constants.DefaultNodeImage, "The image the node container is created from (only supported with the docker driver)")
+	startCmd.Flags().String(kvmDiskFormat, "raw", "The format of the VM disk, raw or qcow2. qcow2 disks are thin provisioned and created from a base image prepared once per ISO. (only supported with the kvm2 driver)")
+	startCmd.Flags().Int(extraDisks, 0, "Number of extra disks created and attached to the VM, each the size of --disk-size. (only supported with the kvm2 driver)")
+	startCmd.Flags().String(kvmPrivateNetworkCIDR, "", fmt.Sprintf("The CIDR of the private network created for the VM. Defaults to the first free /24 network from %s. (only supported with the kvm2 driver)", constants.DefaultKvmPrivateNetworkCIDR))
 	startCmd.Flags().String(xhyveDiskDriver, "ahci-hd", "The disk driver to use [ahci-hd|virtio-blk] (only supported with xhyve driver)")
 	startCmd.Flags().StringSlice(NFSShare, []string{}, "Local folders to share with Guest via NFS mounts (Only supported on with hyperkit now)")
 	startCmd.Flags().String(NFSSharesRoot, "/nfsshares", "Where to root the NFS Shares (defaults to /nfsshares, only supported with hyperkit now)")
 	startCmd.Flags().StringArrayVar(&dockerEnv, "docker-env", nil, "Environment variables to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().StringArrayVar(&dockerOpt, "docker-opt", nil, "Specify arbitrary flags to pass to the Docker daemon. (format: key=value)")
+	startCmd.Flags().StringArrayVar(&driverOpt, "driver-opt", nil, "Specify create flags to pass to a docker-machine driver plugin, without the leading dashes. (format: key=value)")
 	startCmd.Flags().String(apiServerName, constants.APIServerName, "The apiserver name which is used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().StringArrayVar(&apiServerNames, "apiserver-names", nil, "A set of apiserver names which are used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().IPSliceVar(&apiServerIPs, "apiserver-ips", nil, "A set of apiserver IP Addresses which are used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().String(dnsDomain, constants.ClusterDNSDomain, "The cluster dns domain name used in the kubernetes cluster")
-	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon.  The default service CIDR range will automatically be added.")
+	startCmd.Flags().String(serviceClusterIPRange, pkgutil.DefaultServiceCIDR, "The CIDR range of the kubernetes service IPs. The cluster DNS service gets its 10th address")
+	startCmd.Flags().String(podNetworkCIDR, "", "The CIDR range of the pod IPs, passed to kubeadm. It must not overlap the service range")
+	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon.  The service CIDR range will automatically be added.")
 	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
 	startCmd.Flags().String(kubernetesVersion, constants.DefaultKubernetesVersion, "The kubernetes version that the minikube VM will use (ex: v1.2.3) \n OR a URI which contains a localkube binary (ex: https://storage.googleapis.com/minikube/k8sReleases/v1.3.0/localkube-linux-amd64)")
 	startCmd.Flags().String(containerRuntime, "", "The container runtime to be used")
 	startCmd.Flags().String(networkPlugin, "", "The name of the network plugin")
+	startCmd.Flags().String(cniPlugin, "", fmt.Sprintf("The CNI to deploy, one of %s, or the path of a CNI manifest. It sets the pod network to %s unless --%s is given (kubeadm only)", strings.Join(cni.Names(), ", "), cni.DefaultPodCIDR, podNetworkCIDR))
 	startCmd.Flags().String(featureGates, "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
 	startCmd.Flags().Bool(cacheImages, true, "If true, cache docker images for the current bootstrapper and load them into the machine.")
-	startCmd.Flags().Var(&extraOptions, "extra-config",
+	startCmd.Flags().Var(&extraOptions, extraConfig,
 		`A set of key=value pairs that describe configuration that may be passed to different components.
 		The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.
 		Valid components are: kubelet, apiserver, controller-manager, etcd, proxy, scheduler.`)
+	startCmd.Flags().String(kubeletConfigPatch, "", "Path of a YAML patch merged into the KubeletConfiguration generated by minikube (kubeadm and kubernetes v1.10 or later only)")
+	startCmd.Flags().String(kubeProxyConfigPatch, "", "Path of a YAML patch merged into the KubeProxyConfiguration generated by minikube (kubeadm and kubernetes v1.10 or later only)")
+	startCmd.Flags().String(auditPolicy, "", fmt.Sprintf("The audit policy of the apiserver, one of %s, or the path of a policy file. The audit log is read with minikube logs audit (kubeadm and kubernetes v1.10 or later only)", strings.Join(kubeadm.AuditPolicyPresets(), ", ")))
+	startCmd.Flags().String(admissionConfig, "", "Path of the AdmissionConfiguration file of the apiserver (kubeadm and kubernetes v1.10 or later only)")
 	viper.BindPFlags(startCmd.Flags())
 	RootCmd.AddCommand(startCmd)
 }
 
+// readAuditPolicy returns the audit policy preset or file of --audit-policy
+func readAuditPolicy() string {
+	if policy, ok := kubeadm.AuditPolicy(viper.GetString(auditPolicy)); ok {
+		return policy
+	}
+	return readConfigFile(auditPolicy)
+}
+
+// readConfigFile returns the contents of the config file of the flag.
+// The contents are stored in the profile, which minikube upgrade reuses.
+func readConfigFile(flag string) string {
+	path := viper.GetString(flag)
+	if path == "" {
+		return ""
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		glog.Exitf("Error reading --%s: %s", flag, err)
+	}
+	return string(b)
+}
+
+// validateExtraOptions checks the extra options against the components and
+// flags known to the bootstrapper, and prints the options replacing the
+// defaults set by minikube.
+func validateExtraOptions(bootstrapperName string, k8s cfg.KubernetesConfig) error {
+	var warnings []string
+	var err error
+	switch bootstrapperName {
+	case bootstrapper.BootstrapperTypeKubeadm:
+		warnings, err = kubeadm.ValidateExtraOptions(k8s)
+	case bootstrapper.BootstrapperTypeLocalkube:
+		warnings, err = localkube.ValidateExtraOptions(k8s)
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "WARNING: %s\n", w)
+	}
+	return err
+}
+
 // saveConfig saves profile cluster configuration in
 // $MINIKUBE_HOME/profiles/<profilename>/config.json
 func saveConfig(clusterConfig cfg.Config) error {
@@ -452,23 +843,3 @@ func saveConfigToFile(data []byte, file string) error {
 	}
 	return nil
 }
-
-func loadConfigFromFile(profile string) (cfg.Config, error) {
-	var cc cfg.Config
-
-	profileConfigFile := constants.GetProfileFile(profile)
-
-	if _, err := os.Stat(profileConfigFile); os.IsNotExist(err) {
-		return cc, err
-	}
-
-	data, err := ioutil.ReadFile(profileConfigFile)
-	if err != nil {
-		return cc, err
-	}
-
-	if err := json.Unmarshal(data, &cc); err != nil {
-		return cc, err
-	}
-	return cc, nil
-}
@@ -17,16 +17,20 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"text/template"
 
 	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -35,11 +39,14 @@ import (
 )
 
 var statusFormat string
+var statusOutput string
 
 type Status struct {
 	MinikubeStatus   string
 	ClusterStatus    string
 	KubeconfigStatus string
+	Components       []bootstrapper.ComponentStatus
+	CNI              string `json:",omitempty"`
 }
 
 const internalErrorCode = -1
@@ -50,13 +57,25 @@ const (
 	k8sNotRunningStatusFlag      = 1 << 2
 )
 
+// componentStatusFlags are the exit status bits set when a component isn't
+// running. The kubelet and localkube are covered by the cluster bit, and
+// optional components such as DNS don't set any.
+var componentStatusFlags = map[string]int{
+	bootstrapper.APIServer:         1 << 3,
+	bootstrapper.Etcd:              1 << 4,
+	bootstrapper.Scheduler:         1 << 5,
+	bootstrapper.ControllerManager: 1 << 6,
+}
+
 // statusCmd represents the status command
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Gets the status of a local kubernetes cluster",
 	Long: `Gets the status of a local kubernetes cluster.
 	Exit status contains the status of minikube's VM, cluster and kubernetes encoded on it's bits in this order from right to left.
-	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for kubernetes NOK)`,
+	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for kubernetes NOK)
+	The following bits are set for unhealthy cluster components: 8 (apiserver), 16 (etcd), 32 (scheduler)
+	and 64 (controller-manager). DNS is optional, so it doesn't set a bit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var returnCode = 0
 		api, err := machine.NewAPIClient()
@@ -74,19 +93,24 @@ var statusCmd = &cobra.Command{
 
 		cs := state.None.String()
 		ks := state.None.String()
+		var components []bootstrapper.ComponentStatus
 		if ms == state.Running.String() {
 			clusterBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
 			if err != nil {
 				glog.Errorf("Error getting cluster bootstrapper: %s", err)
 				cmdUtil.MaybeReportErrorAndExitWithCode(err, internalErrorCode)
 			}
-			cs, err = clusterBootstrapper.GetClusterStatus()
+			clusterStatus, err := clusterBootstrapper.GetClusterStatus(loadKubernetesConfig())
 			if err != nil {
 				glog.Errorln("Error cluster status:", err)
 				cmdUtil.MaybeReportErrorAndExitWithCode(err, internalErrorCode)
-			} else if cs != state.Running.String() {
+			}
+			cs = clusterStatus.State
+			components = clusterStatus.Components
+			if cs != state.Running.String() {
 				returnCode |= clusterNotRunningStatusFlag
 			}
+			returnCode |= componentsReturnCode(components)
 
 			ip, err := cluster.GetHostDriverIP(api)
 			if err != nil {
@@ -109,26 +133,53 @@ var statusCmd = &cobra.Command{
 			returnCode |= minikubeNotRunningStatusFlag
 		}
 
-		status := Status{ms, cs, ks}
+		status := Status{ms, cs, ks, components, loadKubernetesConfig().CNI}
+		if err := printStatus(os.Stdout, status); err != nil {
+			glog.Errorln("Error printing status:", err)
+			os.Exit(internalErrorCode)
+		}
+
+		os.Exit(returnCode)
+	},
+}
+
+// componentsReturnCode returns the exit status bits of the required components which aren't running
+func componentsReturnCode(components []bootstrapper.ComponentStatus) int {
+	code := 0
+	for _, c := range components {
+		if c.State != state.Running.String() && !c.Optional {
+			code |= componentStatusFlags[c.Name]
+		}
+	}
+	return code
+}
 
+func printStatus(out io.Writer, status Status) error {
+	switch statusOutput {
+	case "":
 		tmpl, err := template.New("status").Parse(statusFormat)
 		if err != nil {
-			glog.Errorln("Error creating status template:", err)
-			os.Exit(internalErrorCode)
+			return errors.Wrap(err, "creating status template")
+		}
+		if err := tmpl.Execute(out, status); err != nil {
+			return errors.Wrap(err, "executing status template")
 		}
-		err = tmpl.Execute(os.Stdout, status)
+	case "json":
+		b, err := json.MarshalIndent(status, "", "    ")
 		if err != nil {
-			glog.Errorln("Error executing status template:", err)
-			os.Exit(internalErrorCode)
+			return errors.Wrap(err, "encoding status")
 		}
-
-		os.Exit(returnCode)
-	},
+		fmt.Fprintln(out, string(b))
+	default:
+		return errors.Errorf("invalid output format %q, must be empty or json", statusOutput)
+	}
+	return nil
 }
 
 func init() {
 	statusCmd.Flags().StringVar(&statusFormat, "format", constants.DefaultStatusFormat,
 		`Go template format string for the status output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
 For the list accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#Status`)
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "", "Output format, either empty to use the --format template, or json")
 	RootCmd.AddCommand(statusCmd)
 }
@@ -0,0 +1,91 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+var testStatus = Status{
+	MinikubeStatus:   "Running",
+	ClusterStatus:    "Error",
+	KubeconfigStatus: "Correctly Configured: pointing to minikube-vm at 192.168.99.100",
+	Components: []bootstrapper.ComponentStatus{
+		{Name: bootstrapper.Kubelet, State: "Running"},
+		{Name: bootstrapper.APIServer, State: "Running"},
+		{Name: bootstrapper.Etcd, State: "Error", Error: "[-]etcd failed"},
+		{Name: bootstrapper.DNS, State: "Stopped", Error: "apiserver is not running", Optional: true},
+	},
+	CNI: "calico",
+}
+
+func TestComponentsReturnCode(t *testing.T) {
+	if code := componentsReturnCode(testStatus.Components); code != 16 {
+		t.Errorf("Expected return code %d, got %d", 16, code)
+	}
+	if code := componentsReturnCode(nil); code != 0 {
+		t.Errorf("Expected return code 0 without components, got %d", code)
+	}
+}
+
+func TestPrintStatus(t *testing.T) {
+	defer func(format, output string) {
+		statusFormat, statusOutput = format, output
+	}(statusFormat, statusOutput)
+
+	statusFormat = constants.DefaultStatusFormat
+	statusOutput = ""
+	var b bytes.Buffer
+	if err := printStatus(&b, testStatus); err != nil {
+		t.Fatalf("Error printing status: %s", err)
+	}
+	expected := `minikube: Running
+cluster: Error
+  kubelet: Running
+  apiserver: Running
+  etcd: Error ([-]etcd failed)
+  dns: Stopped (apiserver is not running)
+cni: calico
+kubectl: Correctly Configured: pointing to minikube-vm at 192.168.99.100
+`
+	if b.String() != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, b.String())
+	}
+
+	statusOutput = "json"
+	b.Reset()
+	if err := printStatus(&b, testStatus); err != nil {
+		t.Fatalf("Error printing json status: %s", err)
+	}
+	var decoded Status
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil {
+		t.Fatalf("Error decoding json status %s: %s", b.String(), err)
+	}
+	if len(decoded.Components) != 4 || decoded.Components[2].Error != "[-]etcd failed" {
+		t.Errorf("Unexpected json status: %s", b.String())
+	}
+
+	statusOutput = "yaml"
+	if err := printStatus(&b, testStatus); err == nil {
+		t.Error("Expected an error for an unknown output format")
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/support"
+	"k8s.io/minikube/pkg/version"
+)
+
+var supportBundleOutput string
+
+// supportCmd represents the support command
+var supportCmd = &cobra.Command{
+	Use:   "support",
+	Short: "Collect diagnostics for reporting minikube issues",
+	Long:  "Collect diagnostics for reporting minikube issues.",
+}
+
+// supportBundleCmd represents the support bundle command
+var supportBundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Write the diagnostics of the cluster to a single tarball",
+	Long: `Write the diagnostics of the cluster to a single tarball: the minikube and machine configs, the driver state,
+the cluster logs, the kubernetes objects, events and nodes, the kubelet and kubeadm configs and the host and VM versions.
+Secrets, such as passwords, tokens and private keys, are redacted. When the cluster is down, the bundle holds whatever could be collected.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		output := supportBundleOutput
+		if output == "" {
+			output = fmt.Sprintf("minikube-support-%s-%s.tar.gz", profile, time.Now().Format("20060102-150405"))
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating the support bundle: %s\n", err)
+			os.Exit(1)
+		}
+		dir := strings.TrimSuffix(filepath.Base(output), ".tar.gz")
+		err = support.WriteBundle(f, dir, supportItems(api, profile))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing the support bundle: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote the support bundle to %s\n", output)
+	},
+}
+
+func init() {
+	supportBundleCmd.Flags().StringVarP(&supportBundleOutput, "output", "o", "", "Path of the tarball to write, defaults to minikube-support-<profile>-<time>.tar.gz in the current directory")
+	supportCmd.AddCommand(supportBundleCmd)
+	RootCmd.AddCommand(supportCmd)
+}
+
+// supportItems returns the diagnostics of the profile. Items which need the
+// VM report an error when it isn't running.
+func supportItems(api libmachine.API, profile string) []support.Item {
+	var runner bootstrapper.CommandRunner
+	vmRunner := func() (bootstrapper.CommandRunner, error) {
+		if runner != nil {
+			return runner, nil
+		}
+		status, err := cluster.GetHostStatus(api)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting the VM status")
+		}
+		if status != state.Running.String() {
+			return nil, errors.Errorf("the VM is not running, its status is %q", status)
+		}
+		h, err := api.Load(profile)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading the machine")
+		}
+		runner, err = machine.GetCommandRunner(h)
+		return runner, err
+	}
+	vmOutput := func(command string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			r, err := vmRunner()
+			if err != nil {
+				return nil, err
+			}
+			out, err := r.CombinedOutput(command)
+			return []byte(out), err
+		}
+	}
+	kubectl := func(args ...string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			return exec.Command("kubectl", append([]string{"--context", profile}, args...)...).CombinedOutput()
+		}
+	}
+	file := func(path string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			return ioutil.ReadFile(path)
+		}
+	}
+
+	return []support.Item{
+		{Name: "versions.txt", Collect: hostVersions},
+		{Name: "vm/versions.txt", Collect: vmOutput("cat /etc/os-release; uname -a; docker version")},
+		{Name: "profile/config.json", Collect: file(constants.GetProfileFile(profile)), JSON: true},
+		{Name: "minikube/config.json", Collect: file(constants.ConfigFile), JSON: true},
+		{Name: "machine/config.json", Collect: file(constants.MakeMiniPath("machines", profile, "config.json")), JSON: true},
+		{Name: "machine/state.txt", Collect: func() ([]byte, error) { return machineState(api, profile) }},
+		{Name: "logs/cluster.log", Collect: func() ([]byte, error) { return clusterLogs(api) }},
+		{Name: "kubernetes/get-all.txt", Collect: kubectl("get", "all", "--all-namespaces", "-o", "wide")},
+		{Name: "kubernetes/events.txt", Collect: kubectl("get", "events", "--all-namespaces")},
+		{Name: "kubernetes/describe-nodes.txt", Collect: kubectl("describe", "nodes")},
+		{Name: "vm/kubelet.service", Collect: vmOutput(fmt.Sprintf("sudo cat %s %s", constants.KubeletServiceFile, constants.KubeletSystemdConfFile))},
+		{Name: "vm/kubeadm.yaml", Collect: vmOutput("sudo cat " + constants.KubeadmConfigFile)},
+		{Name: "vm/kubelet-config.yaml", Collect: vmOutput("sudo cat " + constants.KubeletConfigFile)},
+	}
+}
+
+func hostVersions() ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "minikube: %s\n", version.GetVersion())
+	fmt.Fprintf(&b, "os: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "bootstrapper: %s\n", viper.GetString(cmdcfg.Bootstrapper))
+	out, err := exec.Command("kubectl", "version", "--client", "--short").CombinedOutput()
+	fmt.Fprintf(&b, "kubectl: %s\n", strings.TrimSpace(string(out)))
+	return b.Bytes(), errors.Wrap(err, "getting the kubectl version")
+}
+
+func machineState(api libmachine.API, profile string) ([]byte, error) {
+	h, err := api.Load(profile)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading the machine")
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "driver: %s\n", h.DriverName)
+	s, err := h.Driver.GetState()
+	if err != nil {
+		return b.Bytes(), errors.Wrap(err, "getting the machine state")
+	}
+	fmt.Fprintf(&b, "state: %s\n", s)
+	if s != state.Running {
+		return b.Bytes(), nil
+	}
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		return b.Bytes(), errors.Wrap(err, "getting the machine ip")
+	}
+	fmt.Fprintf(&b, "ip: %s\n", ip)
+	return b.Bytes(), nil
+}
+
+func clusterLogs(api libmachine.API) ([]byte, error) {
+	status, err := cluster.GetHostStatus(api)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the VM status")
+	}
+	if status != state.Running.String() {
+		return nil, errors.Errorf("the VM is not running, its status is %q", status)
+	}
+	b, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the cluster bootstrapper")
+	}
+	var logs bytes.Buffer
+	err = b.GetClusterLogsTo(loadKubernetesConfig(), bootstrapper.LogOptions{}, &logs)
+	return logs.Bytes(), err
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/version"
+)
+
+var upgradeKubernetesVersion string
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrades the kubernetes version of a running cluster",
+	Long: `Upgrades the kubernetes version of a running cluster in place with kubeadm upgrade, keeping its workloads.
+Clusters can only be upgraded one minor version at a time. If the upgrade fails, the previous kubeadm and kubelet
+binaries and configs are restored. Only the kubeadm bootstrapper supports upgrades.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if upgradeKubernetesVersion == "" {
+			fmt.Fprintln(os.Stderr, "Please specify the version to upgrade to with --kubernetes-version")
+			os.Exit(1)
+		}
+		target := upgradeKubernetesVersion
+		if !strings.HasPrefix(target, version.VersionPrefix) {
+			target = version.VersionPrefix + target
+		}
+
+		cc, err := cfg.Load(cfg.GetMachineName())
+		if err != nil {
+			if os.IsNotExist(err) {
+				fmt.Fprintln(os.Stderr, "There is no cluster to upgrade, start one with minikube start")
+			} else {
+				fmt.Fprintf(os.Stderr, "Error loading profile config: %s\n", err)
+			}
+			os.Exit(1)
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		status, err := cluster.GetHostStatus(api)
+		if err != nil {
+			glog.Errorln("Error getting VM status: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+		if status != state.Running.String() {
+			fmt.Fprintln(os.Stderr, "The cluster must be running to be upgraded, start it with minikube start")
+			os.Exit(1)
+		}
+
+		b, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+		if err != nil {
+			glog.Exitf("Error getting cluster bootstrapper: %s", err)
+		}
+
+		current := cc.KubernetesConfig
+		cc.KubernetesConfig.KubernetesVersion = target
+		fmt.Printf("Upgrading the cluster from %s to %s...\n", current.KubernetesVersion, target)
+		upgradeStart := time.Now()
+		if err := b.UpgradeCluster(current, cc.KubernetesConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "Error upgrading the cluster: %s\n", err)
+			maybePrintProblems(b, cc.KubernetesConfig, problemsWindow(upgradeStart))
+			os.Exit(1)
+		}
+
+		if err := saveConfig(cc); err != nil {
+			glog.Errorln("Error saving profile cluster configuration: ", err)
+		}
+		fmt.Printf("The cluster was upgraded to %s.\n", target)
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeKubernetesVersion, "kubernetes-version", "", "The kubernetes version to upgrade the cluster to (ex: v1.10.0)")
+	RootCmd.AddCommand(upgradeCmd)
+}
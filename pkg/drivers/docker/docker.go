@@ -0,0 +1,321 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/ssh"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	driverName = "docker"
+
+	// MachineLabel is the label set on node containers, holding the machine name
+	MachineLabel = "io.k8s.minikube.machine"
+
+	// localhost is the host address the node ports are published on
+	localhost = "127.0.0.1"
+
+	guestSSHPort    = 22
+	guestDockerPort = 2376
+)
+
+// Driver runs the node as a privileged container on the local docker
+// engine. The container image provides systemd, docker and sshd, so the node
+// is provisioned and bootstrapped over SSH just like a VM.
+type Driver struct {
+	*drivers.BaseDriver
+	*pkgdrivers.CommonDriver
+
+	// The node image to run
+	Image string
+
+	// How much memory, in MB, the node may use
+	Memory int
+
+	// How many cpus the node may use
+	CPU int
+
+	// The container engine CLI
+	Binary string
+}
+
+func NewDriver(hostName, storePath string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: hostName,
+			StorePath:   storePath,
+			SSHUser:     "docker",
+		},
+		CommonDriver: &pkgdrivers.CommonDriver{},
+		Binary:       "docker",
+	}
+}
+
+// PreCreateCheck checks that the docker engine can be reached
+func (d *Driver) PreCreateCheck() error {
+	if _, err := exec.LookPath(d.Binary); err != nil {
+		return errors.Wrapf(err, "%s cannot be found on the path. A docker installation is a requirement for using the docker driver", d.Binary)
+	}
+	if _, err := d.run("version"); err != nil {
+		return errors.Wrap(err, "the docker engine is not reachable")
+	}
+	return nil
+}
+
+func (d *Driver) Create() error {
+	log.Info("Creating ssh key...")
+	if err := ssh.GenerateSSHKey(d.GetSSHKeyPath()); err != nil {
+		return errors.Wrap(err, "generating ssh key")
+	}
+
+	log.Infof("Creating node container from %s...", d.Image)
+	if _, err := d.run(d.runArgs()...); err != nil {
+		return errors.Wrap(err, "creating node container")
+	}
+
+	key, err := ioutil.ReadFile(d.GetSSHKeyPath() + ".pub")
+	if err != nil {
+		return errors.Wrap(err, "reading ssh key")
+	}
+	cmd := exec.Command(d.Binary, "exec", "-i", d.MachineName, "sh", "-c",
+		"mkdir -p /home/docker/.ssh && cat > /home/docker/.ssh/authorized_keys && chown -R docker /home/docker/.ssh && chmod 600 /home/docker/.ssh/authorized_keys")
+	cmd.Stdin = bytes.NewReader(key)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "authorizing ssh key: %s", out)
+	}
+
+	log.Info("Waiting for SSH to be available...")
+	return drivers.WaitForSSH(d)
+}
+
+// runArgs returns the arguments creating the node container. The node needs
+// to be privileged to run systemd, docker and the kubelet, and docker's data
+// lives on a volume since it can't be stored on the container's overlay.
+func (d *Driver) runArgs() []string {
+	args := []string{
+		"run", "-d",
+		"--name", d.MachineName,
+		"--hostname", d.MachineName,
+		"--label", fmt.Sprintf("%s=%s", MachineLabel, d.MachineName),
+		"--privileged",
+		"--security-opt", "seccomp=unconfined",
+		"--tmpfs", "/run",
+		"--tmpfs", "/tmp",
+		"-v", "/lib/modules:/lib/modules:ro",
+		"-v", "/var/lib/docker",
+		"-p", fmt.Sprintf("%s::%d", localhost, guestSSHPort),
+		"-p", fmt.Sprintf("%s::%d", localhost, guestDockerPort),
+		"-p", fmt.Sprintf("%s::%d", localhost, util.APIServerPort),
+	}
+	if d.CPU > 0 {
+		args = append(args, "--cpus", strconv.Itoa(d.CPU))
+	}
+	if d.Memory > 0 {
+		args = append(args, "--memory", fmt.Sprintf("%dm", d.Memory))
+	}
+	return append(args, d.Image)
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	return driverName
+}
+
+// GetIP returns the address of the node on the container network
+func (d *Driver) GetIP() (string, error) {
+	out, err := d.inspect("{{.NetworkSettings.IPAddress}}")
+	if err != nil {
+		return "", errors.Wrap(err, "getting node ip")
+	}
+	if net.ParseIP(out) == nil {
+		return "", errors.Errorf("node container %s has no ip address", d.MachineName)
+	}
+	return out, nil
+}
+
+// GetHostIP returns the address of the host on the container network
+func (d *Driver) GetHostIP() (net.IP, error) {
+	out, err := d.inspect("{{.NetworkSettings.Gateway}}")
+	if err != nil {
+		return nil, errors.Wrap(err, "getting container network gateway")
+	}
+	ip := net.ParseIP(out)
+	if ip == nil {
+		return nil, errors.Errorf("node container %s has no gateway", d.MachineName)
+	}
+	return ip, nil
+}
+
+// GetSSHHostname returns hostname for use with ssh. SSH goes through the
+// published port so that it also works when the container network isn't
+// routed to the host.
+func (d *Driver) GetSSHHostname() (string, error) {
+	return localhost, nil
+}
+
+// GetSSHPort returns the host port SSH is published on. The port is looked
+// up every time, since docker picks a new one when the container restarts.
+func (d *Driver) GetSSHPort() (int, error) {
+	return d.HostPort(guestSSHPort)
+}
+
+// HostPort returns the host port a port of the node is published on
+func (d *Driver) HostPort(port int) (int, error) {
+	out, err := d.run("port", d.MachineName, strconv.Itoa(port))
+	if err != nil {
+		return 0, errors.Wrapf(err, "getting host port for %d", port)
+	}
+	return parseHostPort(out)
+}
+
+// parseHostPort parses the output of `docker port`, such as 127.0.0.1:32768
+func parseHostPort(out string) (int, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	_, port, err := net.SplitHostPort(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing published port %q", out)
+	}
+	return strconv.Atoi(port)
+}
+
+// GetAPIServerURL returns the URL of the apiserver published on the host,
+// which works when the container network isn't routed to the host, such as
+// with Docker for Mac and Windows.
+func (d *Driver) GetAPIServerURL() (string, error) {
+	port, err := d.HostPort(util.APIServerPort)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("https://%s", net.JoinHostPort(localhost, strconv.Itoa(port))), nil
+}
+
+// GetDockerHost returns the address of the node's docker daemon published
+// on the host. It uses localhost, which the daemon's certificate is valid for.
+func (d *Driver) GetDockerHost() (string, error) {
+	port, err := d.HostPort(guestDockerPort)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort("localhost", strconv.Itoa(port))), nil
+}
+
+// GetURL returns a Docker compatible host URL for connecting to this host.
+// Its port is the one the daemon listens on in the node, so it is only used
+// to provision the node; clients use GetDockerHost.
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, "2376")), nil
+}
+
+// GetState returns the state that the host is in (running, stopped, etc)
+func (d *Driver) GetState() (state.State, error) {
+	out, err := d.inspect("{{.State.Status}}")
+	if err != nil {
+		if strings.Contains(err.Error(), "No such") {
+			return state.None, nil
+		}
+		return state.Error, errors.Wrap(err, "getting node container state")
+	}
+	return containerState(out), nil
+}
+
+func containerState(status string) state.State {
+	switch status {
+	case "created", "exited", "dead":
+		return state.Stopped
+	case "running":
+		return state.Running
+	case "paused":
+		return state.Paused
+	case "restarting":
+		return state.Starting
+	case "removing":
+		return state.Stopping
+	default:
+		return state.None
+	}
+}
+
+// Start a host
+func (d *Driver) Start() error {
+	if _, err := d.run("start", d.MachineName); err != nil {
+		return errors.Wrap(err, "starting node container")
+	}
+	log.Info("Waiting for SSH to be available...")
+	return drivers.WaitForSSH(d)
+}
+
+// Stop a host gracefully
+func (d *Driver) Stop() error {
+	_, err := d.run("stop", d.MachineName)
+	return err
+}
+
+// Kill stops a host forcefully
+func (d *Driver) Kill() error {
+	_, err := d.run("kill", d.MachineName)
+	return err
+}
+
+func (d *Driver) Restart() error {
+	_, err := d.run("restart", d.MachineName)
+	return err
+}
+
+// Remove a host, along with the volume holding its docker data
+func (d *Driver) Remove() error {
+	if _, err := d.inspect("{{.Id}}"); err != nil {
+		log.Infof("Node container %s not found, assuming it has been removed already", d.MachineName)
+		return nil
+	}
+	_, err := d.run("rm", "-f", "-v", d.MachineName)
+	return err
+}
+
+func (d *Driver) inspect(format string) (string, error) {
+	return d.run("inspect", "-f", format, d.MachineName)
+}
+
+// run runs the container engine CLI, returning its trimmed output
+func (d *Driver) run(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(d.Binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	log.Debugf("Running %s %s", d.Binary, strings.Join(args, " "))
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "%s %s: %s", d.Binary, args[0], strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
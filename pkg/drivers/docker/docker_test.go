@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+)
+
+func TestRunArgs(t *testing.T) {
+	d := NewDriver("minikube", "/store")
+	d.Image = "gcr.io/k8s-minikube/node-image:v0.26.0"
+	d.CPU = 2
+	d.Memory = 2048
+
+	args := d.runArgs()
+	if args[len(args)-1] != d.Image {
+		t.Errorf("Expected the image to be the last argument, got %v", args)
+	}
+	joined := strings.Join(args, " ")
+	for _, expected := range []string{
+		"--name minikube",
+		"--label io.k8s.minikube.machine=minikube",
+		"--privileged",
+		"-p 127.0.0.1::22",
+		"-p 127.0.0.1::2376",
+		"-p 127.0.0.1::8443",
+		"--cpus 2",
+		"--memory 2048m",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected %q in run args: %s", expected, joined)
+		}
+	}
+}
+
+func TestParseHostPort(t *testing.T) {
+	var tests = []struct {
+		out       string
+		expected  int
+		shouldErr bool
+	}{
+		{out: "127.0.0.1:32768\n", expected: 32768},
+		{out: "0.0.0.0:32770\n:::32770\n", expected: 32770},
+		{out: "", shouldErr: true},
+		{out: "127.0.0.1", shouldErr: true},
+	}
+	for _, test := range tests {
+		port, err := parseHostPort(test.out)
+		if test.shouldErr {
+			if err == nil {
+				t.Errorf("Expected error parsing %q", test.out)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", test.out, err)
+			continue
+		}
+		if port != test.expected {
+			t.Errorf("Expected port %d parsing %q, got %d", test.expected, test.out, port)
+		}
+	}
+}
+
+func TestContainerState(t *testing.T) {
+	for status, expected := range map[string]state.State{
+		"running": state.Running,
+		"exited":  state.Stopped,
+		"created": state.Stopped,
+		"paused":  state.Paused,
+		"unknown": state.None,
+	} {
+		if got := containerState(status); got != expected {
+			t.Errorf("Expected state %s for %q, got %s", expected, status, got)
+		}
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package drivers
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -34,6 +35,11 @@ func GetDiskPath(d *drivers.BaseDriver) string {
 	return filepath.Join(d.ResolveStorePath("."), d.GetMachineName()+".rawdisk")
 }
 
+// ExtraDiskPath returns the path of the n-th extra disk of a machine
+func ExtraDiskPath(d *drivers.BaseDriver, n int) string {
+	return filepath.Join(d.ResolveStorePath("."), fmt.Sprintf("%s-%d.rawdisk", d.GetMachineName(), n))
+}
+
 type CommonDriver struct{}
 
 //Not implemented yet
@@ -72,6 +78,48 @@ func createRawDiskImage(sshKeyPath, diskPath string, diskSizeMb int) error {
 	return nil
 }
 
+// CreateRawDisk creates an empty, sparse raw disk image if it doesn't exist
+func CreateRawDisk(diskPath string, diskSizeMb int) error {
+	file, err := os.OpenFile(diskPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrapf(err, "closing file %s", diskPath)
+	}
+	return os.Truncate(diskPath, int64(diskSizeMb*1000000))
+}
+
+// GrowRawDisk grows a raw disk image to diskSizeMb, returning whether it was
+// grown. Disks are never shrunk, since that would destroy the data at the end
+// of the disk.
+func GrowRawDisk(diskPath string, diskSizeMb int) (bool, error) {
+	fi, err := os.Stat(diskPath)
+	if err != nil {
+		return false, err
+	}
+	size := int64(diskSizeMb * 1000000)
+	if size <= fi.Size() {
+		return false, nil
+	}
+	if err := os.Truncate(diskPath, size); err != nil {
+		return false, errors.Wrapf(err, "growing disk %s", diskPath)
+	}
+	return true, nil
+}
+
+// GrowDataPartitionCmd grows the data partition formatted by the ISO, which
+// is the last partition on the disk, and its filesystem to fill the disk.
+const GrowDataPartitionCmd = `set -e
+part=$(sudo blkid -o device -l -t LABEL=boot2docker-data)
+disk=${part%%[0-9]*}
+sudo parted -s "$disk" resizepart "${part#$disk}" 100%
+sudo partprobe "$disk"
+sudo resize2fs "$part"`
+
 func publicSSHKeyPath(d *drivers.BaseDriver) string {
 	return d.GetSSHKeyPath() + ".pub"
 }
@@ -46,3 +46,39 @@ func Test_createDiskImage(t *testing.T) {
 		t.Errorf("Disk size is %v, want %v", fi.Size(), sizeInBytes)
 	}
 }
+
+func TestGrowRawDisk(t *testing.T) {
+	tmpdir := tests.MakeTempDir()
+	defer os.RemoveAll(tmpdir)
+
+	diskPath := filepath.Join(tmpdir, "disk")
+	if err := CreateRawDisk(diskPath, 100); err != nil {
+		t.Fatalf("CreateRawDisk() error = %v", err)
+	}
+
+	var tests = []struct {
+		sizeInMb     int
+		expectGrown  bool
+		expectedSize int64
+	}{
+		{sizeInMb: 200, expectGrown: true, expectedSize: 200 * 1000000},
+		{sizeInMb: 200, expectGrown: false, expectedSize: 200 * 1000000},
+		{sizeInMb: 50, expectGrown: false, expectedSize: 200 * 1000000},
+	}
+	for _, test := range tests {
+		grown, err := GrowRawDisk(diskPath, test.sizeInMb)
+		if err != nil {
+			t.Fatalf("GrowRawDisk() error = %v", err)
+		}
+		if grown != test.expectGrown {
+			t.Errorf("GrowRawDisk(%d) grown = %v, want %v", test.sizeInMb, grown, test.expectGrown)
+		}
+		fi, err := os.Stat(diskPath)
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if fi.Size() != test.expectedSize {
+			t.Errorf("Disk size is %v, want %v", fi.Size(), test.expectedSize)
+		}
+	}
+}
@@ -21,10 +21,14 @@ import (
 	"crypto/rand"
 	"fmt"
 	"net"
+	"regexp"
+	"strconv"
 	"text/template"
 
+	"github.com/docker/machine/libmachine/log"
 	libvirt "github.com/libvirt/libvirt-go"
 	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
 )
 
 const domainTmpl = `
@@ -51,10 +55,17 @@ const domainTmpl = `
       <readonly/>
     </disk>
     <disk type='file' device='disk'>
-      <driver name='qemu' type='raw' cache='default' io='threads' />
+      <driver name='qemu' type='{{.DiskFormat}}' cache='default' io='threads' />
       <source file='{{.DiskPath}}'/>
       <target dev='hda' bus='virtio'/>
     </disk>
+    {{range .ExtraDiskDevices}}
+    <disk type='file' device='disk'>
+      <driver name='qemu' type='raw' cache='default' io='threads' />
+      <source file='{{.Path}}'/>
+      <target dev='{{.Target}}' bus='virtio'/>
+    </disk>
+    {{end}}
     <interface type='network'>
       <source network='{{.Network}}'/>
       <mac address='{{.MAC}}'/>
@@ -104,6 +115,28 @@ $ newgrp libvirt
 Visit https://github.com/kubernetes/minikube/blob/master/docs/drivers.md#kvm-driver for more information.
 `
 
+type extraDisk struct {
+	Path   string
+	Target string
+}
+
+type domainConfig struct {
+	*Driver
+	ExtraDiskDevices []extraDisk
+}
+
+func (d *Driver) extraDiskDevices() []extraDisk {
+	var disks []extraDisk
+	for i := 0; i < d.ExtraDisks; i++ {
+		disks = append(disks, extraDisk{
+			Path: pkgdrivers.ExtraDiskPath(d.BaseDriver, i),
+			// hda and hdc are taken by the main disk and the ISO
+			Target: fmt.Sprintf("vd%c", 'b'+i),
+		})
+	}
+	return disks
+}
+
 func randomMAC() (net.HardwareAddr, error) {
 	buf := make([]byte, 6)
 	_, err := rand.Read(buf)
@@ -157,7 +190,7 @@ func closeDomain(dom *libvirt.Domain, conn *libvirt.Connect) error {
 func (d *Driver) createDomain() (*libvirt.Domain, error) {
 	tmpl := template.Must(template.New("domain").Parse(domainTmpl))
 	var domainXml bytes.Buffer
-	if err := tmpl.Execute(&domainXml, d); err != nil {
+	if err := tmpl.Execute(&domainXml, domainConfig{Driver: d, ExtraDiskDevices: d.extraDiskDevices()}); err != nil {
 		return nil, errors.Wrap(err, "executing domain xml")
 	}
 
@@ -174,3 +207,79 @@ func (d *Driver) createDomain() (*libvirt.Domain, error) {
 
 	return dom, nil
 }
+
+var (
+	memoryRe        = regexp.MustCompile(`<memory unit='(\w+)'>(\d+)</memory>`)
+	currentMemoryRe = regexp.MustCompile(`<currentMemory unit='\w+'>\d+</currentMemory>`)
+	vcpuRe          = regexp.MustCompile(`<vcpu([^>]*)>(\d+)</vcpu>`)
+	vcpuCurrentRe   = regexp.MustCompile(` current='\d+'`)
+
+	// memoryUnits are the sizes of the units libvirt accepts, in bytes
+	memoryUnits = map[string]int64{
+		"b": 1, "bytes": 1,
+		"KB": 1000, "k": 1024, "KiB": 1024,
+		"MB": 1000 * 1000, "M": 1024 * 1024, "MiB": 1024 * 1024,
+		"GB": 1000 * 1000 * 1000, "G": 1024 * 1024 * 1024, "GiB": 1024 * 1024 * 1024,
+	}
+)
+
+// updateDomainResources applies the memory and cpus of the driver to a
+// stopped domain, which may have been changed since it was defined.
+func (d *Driver) updateDomainResources(dom *libvirt.Domain, conn *libvirt.Connect) error {
+	domainXml, err := dom.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE)
+	if err != nil {
+		return errors.Wrap(err, "getting domain xml")
+	}
+	updated, changed, err := setDomainResources(domainXml, d.Memory, d.CPU)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	log.Infof("Setting the domain memory to %dMB and cpus to %d...", d.Memory, d.CPU)
+	newDom, err := conn.DomainDefineXML(updated)
+	if err != nil {
+		return errors.Wrapf(err, "Error defining domain xml: %s", updated)
+	}
+	newDom.Free()
+	return nil
+}
+
+// setDomainResources sets the memory, in MB, and the cpus of a domain xml,
+// and returns whether they were changed.
+func setDomainResources(domainXml string, memory, cpus int) (string, bool, error) {
+	m := memoryRe.FindStringSubmatch(domainXml)
+	if m == nil {
+		return "", false, errors.New("domain xml has no memory")
+	}
+	unit, ok := memoryUnits[m[1]]
+	if !ok {
+		return "", false, errors.Errorf("unknown memory unit %s", m[1])
+	}
+	size, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil {
+		return "", false, errors.Wrap(err, "parsing domain memory")
+	}
+	// libvirt stores the memory in KiB, so round it to the nearest MB
+	currentMemory := int((size*unit + 500*1000) / (1000 * 1000))
+
+	v := vcpuRe.FindStringSubmatch(domainXml)
+	if v == nil {
+		return "", false, errors.New("domain xml has no vcpu")
+	}
+	currentCPUs, err := strconv.Atoi(v[2])
+	if err != nil {
+		return "", false, errors.Wrap(err, "parsing domain vcpu")
+	}
+
+	if currentMemory == memory && currentCPUs == cpus {
+		return domainXml, false, nil
+	}
+	domainXml = memoryRe.ReplaceAllLiteralString(domainXml, fmt.Sprintf("<memory unit='MB'>%d</memory>", memory))
+	domainXml = currentMemoryRe.ReplaceAllLiteralString(domainXml, fmt.Sprintf("<currentMemory unit='MB'>%d</currentMemory>", memory))
+	// All the vcpus are made current, as a stale count could exceed the new one
+	attrs := vcpuCurrentRe.ReplaceAllLiteralString(v[1], "")
+	domainXml = vcpuRe.ReplaceAllLiteralString(domainXml, fmt.Sprintf("<vcpu%s>%d</vcpu>", attrs, cpus))
+	return domainXml, true, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvm
+
+import (
+	"strings"
+	"testing"
+)
+
+// A domain defined with 2048MB of memory and 2 cpus, as dumped by libvirt
+const definedDomain = `<domain type='kvm'>
+  <name>minikube</name>
+  <memory unit='KiB'>2000000</memory>
+  <currentMemory unit='KiB'>2000000</currentMemory>
+  <vcpu placement='static' current='1'>2</vcpu>
+  <devices>
+    <disk type='file' device='disk'/>
+  </devices>
+</domain>`
+
+func TestSetDomainResources(t *testing.T) {
+	var tests = []struct {
+		description string
+		memory      int
+		cpus        int
+		changed     bool
+		expected    []string
+	}{
+		{
+			description: "unchanged",
+			memory:      2048,
+			cpus:        2,
+			expected:    []string{"<memory unit='KiB'>2000000</memory>", "<vcpu placement='static' current='1'>2</vcpu>"},
+		},
+		{
+			description: "memory",
+			memory:      4096,
+			cpus:        2,
+			changed:     true,
+			expected: []string{
+				"<memory unit='MB'>4096</memory>",
+				"<currentMemory unit='MB'>4096</currentMemory>",
+				"<vcpu placement='static'>2</vcpu>",
+			},
+		},
+		{
+			description: "cpus",
+			memory:      2048,
+			cpus:        4,
+			changed:     true,
+			expected:    []string{"<vcpu placement='static'>4</vcpu>", "<disk type='file' device='disk'/>"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, changed, err := setDomainResources(definedDomain, test.memory, test.cpus)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if changed != test.changed {
+				t.Errorf("Expected changed to be %t, got %t", test.changed, changed)
+			}
+			for _, e := range test.expected {
+				if !strings.Contains(got, e) {
+					t.Errorf("Expected %q in domain xml, got:\n%s", e, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSetDomainResourcesInvalid(t *testing.T) {
+	if _, _, err := setDomainResources("<domain><vcpu>2</vcpu></domain>", 2048, 2); err == nil {
+		t.Errorf("Expected an error for a domain without memory")
+	}
+	if _, _, err := setDomainResources("<domain><memory unit='KiB'>2000000</memory></domain>", 2048, 2); err == nil {
+		t.Errorf("Expected an error for a domain without vcpu")
+	}
+}
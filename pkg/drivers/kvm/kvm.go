@@ -22,7 +22,6 @@ import (
 	"path/filepath"
 	"time"
 
-	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/util"
 
@@ -50,12 +49,22 @@ type Driver struct {
 	// The name of the private network
 	PrivateNetwork string
 
+	// The CIDR of the private network, such as 192.168.39.0/24
+	PrivateNetworkCIDR string
+
 	// The size of the disk to be created for the VM, in MB
 	DiskSize int
 
 	// The path of the disk .img
 	DiskPath string
 
+	// The format of the disk, raw or qcow2. qcow2 disks are thin provisioned
+	// and backed by a base image prepared once per ISO.
+	DiskFormat string
+
+	// The number of extra disks to create and attach to the VM, each of DiskSize
+	ExtraDisks int
+
 	// A file or network URI to fetch the minikube ISO
 	Boot2DockerURL string
 
@@ -68,11 +77,17 @@ type Driver struct {
 }
 
 const (
-	qemusystem                = "qemu:///system"
-	defaultPrivateNetworkName = "minikube-net"
-	defaultNetworkName        = "default"
+	qemusystem         = "qemu:///system"
+	defaultNetworkName = "default"
 )
 
+// privateNetworkName returns the name of the libvirt network created for a
+// machine, so that each profile gets its own network. The default profile
+// keeps the minikube-net name used before networks were per profile.
+func privateNetworkName(machineName string) string {
+	return fmt.Sprintf("%s-net", machineName)
+}
+
 func NewDriver(hostName, storePath string) *Driver {
 	return &Driver{
 		BaseDriver: &drivers.BaseDriver{
@@ -80,15 +95,17 @@ func NewDriver(hostName, storePath string) *Driver {
 			StorePath:   storePath,
 			SSHUser:     "docker",
 		},
-		CommonDriver:   &pkgdrivers.CommonDriver{},
-		Boot2DockerURL: constants.DefaultIsoUrl,
-		CPU:            constants.DefaultCPUS,
-		DiskSize:       util.CalculateDiskSizeInMB(constants.DefaultDiskSize),
-		Memory:         constants.DefaultMemory,
-		PrivateNetwork: defaultPrivateNetworkName,
-		Network:        defaultNetworkName,
-		DiskPath:       filepath.Join(constants.GetMinipath(), "machines", config.GetMachineName(), fmt.Sprintf("%s.rawdisk", config.GetMachineName())),
-		ISO:            filepath.Join(constants.GetMinipath(), "machines", config.GetMachineName(), "boot2docker.iso"),
+		CommonDriver:       &pkgdrivers.CommonDriver{},
+		Boot2DockerURL:     constants.DefaultIsoUrl,
+		CPU:                constants.DefaultCPUS,
+		DiskSize:           util.CalculateDiskSizeInMB(constants.DefaultDiskSize),
+		Memory:             constants.DefaultMemory,
+		PrivateNetwork:     privateNetworkName(hostName),
+		PrivateNetworkCIDR: constants.DefaultKvmPrivateNetworkCIDR,
+		Network:            defaultNetworkName,
+		DiskPath:           filepath.Join(storePath, "machines", hostName, fmt.Sprintf("%s.rawdisk", hostName)),
+		DiskFormat:         pkgdrivers.DiskFormatRaw,
+		ISO:                filepath.Join(storePath, "machines", hostName, "boot2docker.iso"),
 	}
 }
 
@@ -212,6 +229,25 @@ func (d *Driver) Start() error {
 	}
 	defer closeDomain(dom, conn)
 
+	// The disk size may have been changed since the machine was created,
+	// the guest filesystem is grown after boot
+	grow := pkgdrivers.GrowRawDisk
+	if d.DiskFormat == pkgdrivers.DiskFormatQcow2 {
+		grow = pkgdrivers.GrowQcow2Disk
+	}
+	grown, err := grow(d.DiskPath, d.DiskSize)
+	if err != nil {
+		return errors.Wrap(err, "resizing disk")
+	}
+	if grown {
+		log.Infof("Grew disk to %dMB", d.DiskSize)
+	}
+
+	// The memory and cpus may have been changed since the machine was created
+	if err := d.updateDomainResources(dom, conn); err != nil {
+		return errors.Wrap(err, "updating domain resources")
+	}
+
 	log.Info("Creating domain...")
 	if err := dom.Create(); err != nil {
 		return errors.Wrap(err, "Error creating VM")
@@ -276,9 +312,20 @@ func (d *Driver) Create() error {
 	}
 
 	log.Info("Building disk image...")
-	if err = pkgdrivers.MakeDiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize); err != nil {
+	fromBase := false
+	if d.DiskFormat == pkgdrivers.DiskFormatQcow2 {
+		fromBase, err = pkgdrivers.MakeQcow2DiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize, d.baseImagePath())
+	} else {
+		err = pkgdrivers.MakeDiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize)
+	}
+	if err != nil {
 		return errors.Wrap(err, "Error creating disk")
 	}
+	for i := 0; i < d.ExtraDisks; i++ {
+		if err := pkgdrivers.CreateRawDisk(pkgdrivers.ExtraDiskPath(d.BaseDriver, i), d.DiskSize); err != nil {
+			return errors.Wrap(err, "Error creating extra disk")
+		}
+	}
 
 	log.Info("Creating domain...")
 	dom, err := d.createDomain()
@@ -288,6 +335,43 @@ func (d *Driver) Create() error {
 	defer dom.Free()
 
 	log.Debug("Finished creating machine, now starting machine...")
+	if err := d.Start(); err != nil {
+		return err
+	}
+	if d.DiskFormat != pkgdrivers.DiskFormatQcow2 {
+		return nil
+	}
+	if fromBase {
+		// The base image may be smaller than the disk
+		if _, err := drivers.RunSSHCommandFromDriver(d, pkgdrivers.GrowDataPartitionCmd); err != nil {
+			log.Warnf("Unable to grow the data partition: %s", err)
+		}
+	} else if err := d.prepareBaseImage(); err != nil {
+		return err
+	}
+	// Every machine created from the base image would share its key otherwise
+	if err := pkgdrivers.RotateSSHKey(d, d.BaseDriver); err != nil {
+		return errors.Wrap(err, "replacing the base image ssh key")
+	}
+	return nil
+}
+
+func (d *Driver) baseImagePath() string {
+	return pkgdrivers.BaseImagePath(filepath.Join(d.StorePath, "cache", "kvm"), d.Boot2DockerURL)
+}
+
+// prepareBaseImage saves the disk of a machine that has just booted for the
+// first time, and so has had its disk formatted by the ISO, as the base image
+// for later machines using the same ISO.
+func (d *Driver) prepareBaseImage() error {
+	log.Infof("Preparing base image %s...", d.baseImagePath())
+	if err := d.Stop(); err != nil {
+		return errors.Wrap(err, "stopping machine to prepare base image")
+	}
+	if err := pkgdrivers.SaveBaseImage(d.BaseDriver, d.baseImagePath()); err != nil {
+		// The machine's own disk is still usable
+		log.Warnf("Unable to prepare base image: %s", err)
+	}
 	return d.Start()
 }
 
@@ -335,22 +419,10 @@ func (d *Driver) Remove() error {
 	}
 	defer conn.Close()
 
-	//Tear down network and disk if they exist
-	log.Debug("Checking if the network needs to be deleted")
-	network, err := conn.LookupNetworkByName(d.PrivateNetwork)
-	if err != nil {
-		log.Warn("Network %s does not exist, nothing to clean up...", d.PrivateNetwork)
-	}
-	if network != nil {
-		log.Infof("Network %s exists, removing...", d.PrivateNetwork)
-		network.Destroy()
-		network.Undefine()
-	}
-
 	log.Debug("Checking if the domain needs to be deleted")
 	dom, err := conn.LookupDomainByName(d.MachineName)
 	if err != nil {
-		log.Warn("Domain %s does not exist, nothing to clean up...", d.MachineName)
+		log.Warnf("Domain %s does not exist, nothing to clean up...", d.MachineName)
 	}
 	if dom != nil {
 		log.Infof("Domain %s exists, removing...", d.MachineName)
@@ -358,5 +430,30 @@ func (d *Driver) Remove() error {
 		dom.Undefine()
 	}
 
+	if d.DiskFormat == pkgdrivers.DiskFormatQcow2 {
+		// The machine's disk is removed with its directory, base images are
+		// kept as long as another machine's disk is backed by them
+		if err := pkgdrivers.RemoveUnusedBaseImages(filepath.Join(d.StorePath, "cache", "kvm"), filepath.Join(d.StorePath, "machines"), d.ResolveStorePath(".")); err != nil {
+			log.Warnf("Unable to remove unused base images: %s", err)
+		}
+	}
+
+	// Machines created before networks were per profile may share their
+	// network with other profiles, so only tear down the machine's own network
+	if d.PrivateNetwork != privateNetworkName(d.MachineName) {
+		log.Infof("Network %s is not owned by %s, leaving it in place", d.PrivateNetwork, d.MachineName)
+		return nil
+	}
+	log.Debug("Checking if the network needs to be deleted")
+	network, err := conn.LookupNetworkByName(d.PrivateNetwork)
+	if err != nil {
+		log.Warnf("Network %s does not exist, nothing to clean up...", d.PrivateNetwork)
+	}
+	if network != nil {
+		log.Infof("Network %s exists, removing...", d.PrivateNetwork)
+		network.Destroy()
+		network.Undefine()
+	}
+
 	return nil
 }
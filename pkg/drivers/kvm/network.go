@@ -27,22 +27,27 @@ import (
 	"github.com/docker/machine/libmachine/log"
 	libvirt "github.com/libvirt/libvirt-go"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
 )
 
-// Replace with hardcoded range with CIDR
-// https://play.golang.org/p/m8TNTtygK0
 const networkTmpl = `
 <network>
-  <name>{{.PrivateNetwork}}</name>
+  <name>{{.Name}}</name>
   <dns enable='no'/>
-  <ip address='192.168.39.1' netmask='255.255.255.0'>
+  <ip address='{{.Gateway}}' netmask='{{.Netmask}}'>
     <dhcp>
-      <range start='192.168.39.2' end='192.168.39.254'/>
+      <range start='{{.DHCPStart}}' end='{{.DHCPEnd}}'/>
     </dhcp>
   </ip>
 </network>
 `
 
+type networkConfig struct {
+	Name string
+	*util.PrivateNetwork
+}
+
 func setupNetwork(conn *libvirt.Connect, name string) error {
 	n, err := conn.LookupNetworkByName(defaultNetworkName)
 	if err != nil {
@@ -85,9 +90,18 @@ func (d *Driver) createNetwork() error {
 	}
 	defer conn.Close()
 
+	cidr := d.PrivateNetworkCIDR
+	if cidr == "" {
+		cidr = constants.DefaultKvmPrivateNetworkCIDR
+	}
+	privateNetwork, err := util.ParsePrivateNetwork(cidr)
+	if err != nil {
+		return errors.Wrap(err, "parsing private network cidr")
+	}
+
 	tmpl := template.Must(template.New("network").Parse(networkTmpl))
 	var networkXML bytes.Buffer
-	if err := tmpl.Execute(&networkXML, d); err != nil {
+	if err := tmpl.Execute(&networkXML, networkConfig{Name: d.PrivateNetwork, PrivateNetwork: privateNetwork}); err != nil {
 		return errors.Wrap(err, "executing network template")
 	}
 
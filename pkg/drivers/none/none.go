@@ -17,25 +17,44 @@ limitations under the License.
 package none
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
+	stdnet "net"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/golang/glog"
 
 	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/net"
 	pkgdrivers "k8s.io/minikube/pkg/drivers"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
 )
 
 const driverName = "none"
-const dockerstopcmd = `docker kill $(docker ps -a --filter="name=k8s_" --format="{{.ID}}")`
 
-var dockerkillcmd = fmt.Sprintf(`docker rm $(%s)`, dockerstopcmd)
+// Containers created by the kubelet are labelled with their pod
+const dockerstopcmd = `docker ps -q --filter=label=io.kubernetes.pod.namespace | xargs -r docker kill`
+const dockerkillcmd = `docker ps -aq --filter=label=io.kubernetes.pod.namespace | xargs -r docker rm -f -v`
+
+// The ports the cluster listens on, which must be free on the host
+var clusterPorts = []int{util.APIServerPort, 10250}
+
+const (
+	procSwaps = "/proc/swaps"
+	// systemdRuntimeDir exists when systemd is the init system, see sd_booted(3)
+	systemdRuntimeDir = "/run/systemd/system"
+)
 
 // none Driver is a driver designed to run localkube w/o a VM
 type Driver struct {
@@ -53,18 +72,67 @@ func NewDriver(hostName, storePath string) *Driver {
 	}
 }
 
-// PreCreateCheck checks for correct privileges and dependencies
+// PreCreateCheck checks that the host can run the cluster
 func (d *Driver) PreCreateCheck() error {
+	m := util.MultiError{}
 	// check that docker is on path
-	_, err := exec.LookPath("docker")
-	if err != nil {
-		return errors.Wrap(err, "docker cannot be found on the path for this machine. "+
-			"A docker installation is a requirement for using the none driver")
+	if _, err := exec.LookPath("docker"); err != nil {
+		m.Collect(errors.Wrap(err, "docker cannot be found on the path for this machine. "+
+			"A docker installation is a requirement for using the none driver"))
+	}
+	m.Collect(checkInitSystem(systemdRuntimeDir))
+	m.Collect(checkPorts(clusterPorts))
+
+	if f, err := os.Open(procSwaps); err == nil {
+		// The kubelet is configured to tolerate swap, but it can't enforce
+		// memory limits reliably with it
+		if swaps := activeSwaps(f); len(swaps) > 0 {
+			log.Warnf("Swap is enabled on %s, pod memory limits may not be enforced. Disable it with `sudo swapoff -a`.", strings.Join(swaps, ", "))
+		}
+		f.Close()
+	}
+
+	if err := m.ToError(); err != nil {
+		return errors.Wrap(err, "the host doesn't meet the requirements of the none driver")
+	}
+	return nil
+}
+
+// checkInitSystem checks that systemd, which runs the cluster services, is
+// the init system
+func checkInitSystem(runtimeDir string) error {
+	if _, err := os.Stat(runtimeDir); err != nil {
+		return errors.New("systemd is not the init system of this machine. The none driver requires systemd")
 	}
+	return nil
+}
 
+// checkPorts checks that nothing is listening on the cluster ports
+func checkPorts(ports []int) error {
+	for _, port := range ports {
+		l, err := stdnet.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			return errors.Wrapf(err, "port %d is in use, it is required by the cluster", port)
+		}
+		l.Close()
+	}
 	return nil
 }
 
+// activeSwaps returns the swap devices listed in the /proc/swaps format
+func activeSwaps(r io.Reader) []string {
+	var swaps []string
+	scanner := bufio.NewScanner(r)
+	// Skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			swaps = append(swaps, fields[0])
+		}
+	}
+	return swaps
+}
+
 func (d *Driver) Create() error {
 	// creation for the none driver is handled by commands.go
 	return nil
@@ -100,30 +168,53 @@ func (d *Driver) GetURL() (string, error) {
 	return fmt.Sprintf("tcp://%s:2376", ip), nil
 }
 
+// GetState returns Running if any of the cluster services is running. The
+// state of a removed cluster, whose services are gone, is None.
 func (d *Driver) GetState() (state.State, error) {
-	var statuscmd = fmt.Sprintf("if [[ `systemctl` =~ -\\.mount ]] &>/dev/null; "+`then
-  sudo systemctl is-active kubelet localkube &>/dev/null && echo "Running" || echo "Stopped"
-else
-  if ps $(cat %s) &>/dev/null; then
-    echo "Running"
-  else
-    echo "Stopped"
-  fi
-fi
-`, constants.LocalkubePIDPath)
+	if checkInitSystem(systemdRuntimeDir) != nil {
+		if processRunning(constants.LocalkubePIDPath) {
+			return state.Running, nil
+		}
+		return state.Stopped, nil
+	}
 
-	out, err := runCommand(statuscmd, true)
+	for _, svc := range []string{"kubelet", "localkube"} {
+		out, _ := exec.Command("systemctl", "is-active", svc).Output()
+		switch strings.TrimSpace(string(out)) {
+		case "active", "activating", "reloading":
+			return state.Running, nil
+		}
+	}
+	installed := false
+	for _, unit := range []string{constants.KubeletServiceFile, constants.LocalkubeServicePath} {
+		if _, err := os.Stat(unit); err == nil {
+			installed = true
+		}
+	}
+	if !installed {
+		return state.None, nil
+	}
+	return state.Stopped, nil
+}
+
+// processRunning returns whether the process in pidFile is running
+func processRunning(pidFile string) bool {
+	data, err := ioutil.ReadFile(pidFile)
 	if err != nil {
-		return state.None, err
+		return false
 	}
-	s := strings.TrimSpace(out)
-	if state.Running.String() == s {
-		return state.Running, nil
-	} else if state.Stopped.String() == s {
-		return state.Stopped, nil
-	} else {
-		return state.None, fmt.Errorf("Error: Unrecognize output from GetLocalkubeStatus: %s", s)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
 	}
+	// Sending a signal of 0 checks the existence of a process. Processes of
+	// other users exist but can't be signalled.
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || err == syscall.EPERM
 }
 
 func (d *Driver) Kill() error {
@@ -139,13 +230,23 @@ func (d *Driver) Kill() error {
 	return nil
 }
 
+// Remove tears the cluster down: kubeadm state, the cluster services, the
+// kubelet's containers and the configuration and state directories.
 func (d *Driver) Remove() error {
-	rmCmd := `for svc in "localkube" "kubelet"; do
-		sudo systemctl stop "$svc".service
+	rmCmd := fmt.Sprintf(`if [ -x /usr/bin/kubeadm ]; then
+		/usr/bin/kubeadm reset --force || /usr/bin/kubeadm reset
+	fi
+	for svc in "localkube" "kubelet"; do
+		systemctl stop "$svc".service
+		systemctl disable "$svc".service
 	done
-	sudo rm -rf /data
-	sudo rm -rf /etc/kubernetes/manifests
-	sudo rm -rf /var/lib/localkube || true`
+	rm -f %s %s %s
+	systemctl daemon-reload
+	rm -rf /data
+	rm -rf /etc/kubernetes /var/lib/kubelet /var/lib/etcd %s
+	rm -rf /etc/cni/net.d /var/lib/cni
+	rm -rf /var/lib/localkube || true`,
+		constants.KubeletServiceFile, constants.KubeletSystemdConfFile, constants.LocalkubeServicePath, constants.KubeadmConfigFile)
 
 	for _, cmdStr := range []string{rmCmd, dockerkillcmd} {
 		if out, err := runCommand(cmdStr, true); err != nil {
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package none
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestActiveSwaps(t *testing.T) {
+	swaps := activeSwaps(strings.NewReader(`Filename				Type		Size	Used	Priority
+/dev/sda2                               partition	8388604	0	-2
+/swapfile                               file		2097148	0	-3
+`))
+	if strings.Join(swaps, ",") != "/dev/sda2,/swapfile" {
+		t.Errorf("Expected /dev/sda2 and /swapfile, got %v", swaps)
+	}
+
+	if swaps := activeSwaps(strings.NewReader("Filename	Type	Size	Used	Priority\n")); len(swaps) != 0 {
+		t.Errorf("Expected no swaps, got %v", swaps)
+	}
+}
+
+func TestCheckInitSystem(t *testing.T) {
+	dir := tests.MakeTempDir()
+	defer os.RemoveAll(dir)
+
+	if err := checkInitSystem(dir); err != nil {
+		t.Errorf("Unexpected error with a systemd runtime dir: %s", err)
+	}
+	if err := checkInitSystem(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("Expected an error without a systemd runtime dir")
+	}
+}
+
+func TestCheckPorts(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	used := l.Addr().(*net.TCPAddr).Port
+	if err := checkPorts([]int{used}); err == nil {
+		t.Errorf("Expected an error for port %d in use", used)
+	}
+
+	l.Close()
+	if err := checkPorts([]int{used}); err != nil {
+		t.Errorf("Unexpected error for free port %d: %s", used, err)
+	}
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudflare/cfssl/log"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/ssh"
+	"github.com/pkg/errors"
+)
+
+// Disk formats supported by the KVM drivers
+const (
+	DiskFormatRaw   = "raw"
+	DiskFormatQcow2 = "qcow2"
+)
+
+// ImageInfo is the subset of `qemu-img info` output used by minikube.
+type ImageInfo struct {
+	Format              string `json:"format"`
+	VirtualSize         int64  `json:"virtual-size"`
+	BackingFilename     string `json:"backing-filename"`
+	FullBackingFilename string `json:"full-backing-filename"`
+}
+
+func qemuImg(args ...string) ([]byte, error) {
+	out, err := exec.Command("qemu-img", args...).CombinedOutput()
+	if err != nil {
+		return out, errors.Wrapf(err, "qemu-img %s: %s", strings.Join(args, " "), out)
+	}
+	return out, nil
+}
+
+// QemuImgInfo returns information about the disk image at path.
+func QemuImgInfo(path string) (*ImageInfo, error) {
+	out, err := exec.Command("qemu-img", "info", "--output=json", path).Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting image info for %s", path)
+	}
+	return parseImageInfo(out)
+}
+
+func parseImageInfo(out []byte) (*ImageInfo, error) {
+	info := &ImageInfo{}
+	if err := json.Unmarshal(out, info); err != nil {
+		return nil, errors.Wrap(err, "parsing image info")
+	}
+	if info.FullBackingFilename == "" {
+		info.FullBackingFilename = info.BackingFilename
+	}
+	return info, nil
+}
+
+// BackingChain returns the backing files of the disk image at path, nearest
+// first.
+func BackingChain(path string) ([]string, error) {
+	var chain []string
+	for {
+		info, err := QemuImgInfo(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.FullBackingFilename == "" {
+			return chain, nil
+		}
+		path = info.FullBackingFilename
+		for _, p := range chain {
+			if p == path {
+				return nil, errors.Errorf("backing chain of %s loops at %s", chain[0], path)
+			}
+		}
+		chain = append(chain, path)
+	}
+}
+
+// CreateQcow2Disk creates a thin provisioned qcow2 image. If backingFile is
+// set, the image only stores the clusters that differ from it.
+func CreateQcow2Disk(diskPath string, diskSizeMb int, backingFile string) error {
+	args := []string{"create", "-f", DiskFormatQcow2}
+	if backingFile != "" {
+		args = append(args, "-o", fmt.Sprintf("backing_file=%s,backing_fmt=%s", backingFile, DiskFormatQcow2))
+	}
+	args = append(args, diskPath, fmt.Sprintf("%dM", diskSizeMb))
+	_, err := qemuImg(args...)
+	return err
+}
+
+// GrowQcow2Disk grows a qcow2 image to diskSizeMb, returning whether it was
+// grown. As with GrowRawDisk, disks are never shrunk.
+func GrowQcow2Disk(diskPath string, diskSizeMb int) (bool, error) {
+	info, err := QemuImgInfo(diskPath)
+	if err != nil {
+		return false, err
+	}
+	if int64(diskSizeMb*1000000) <= info.VirtualSize {
+		return false, nil
+	}
+	if _, err := qemuImg("resize", diskPath, fmt.Sprintf("%dM", diskSizeMb)); err != nil {
+		return false, errors.Wrapf(err, "growing disk %s", diskPath)
+	}
+	return true, nil
+}
+
+// BaseImagePath returns the path of the qcow2 base image prepared for an ISO.
+// The ISO URL is hashed so that custom ISOs with the same file name don't
+// share a base image.
+func BaseImagePath(cacheDir, isoURL string) string {
+	name := strings.TrimSuffix(path.Base(isoURL), ".iso")
+	sum := sha256.Sum256([]byte(isoURL))
+	return filepath.Join(cacheDir, fmt.Sprintf("%s-%x.qcow2", name, sum[:4]))
+}
+
+// baseImageKeyPath returns the path of the SSH key baked into a base image.
+func baseImageKeyPath(baseImage string) string {
+	return strings.TrimSuffix(baseImage, ".qcow2") + ".id_rsa"
+}
+
+// MakeQcow2DiskImage copies the ISO to the machine directory and creates a
+// qcow2 disk for it. If a base image was prepared for the ISO, the disk is
+// created on top of it and the machine boots with the SSH key baked into it,
+// which RotateSSHKey must replace; it returns true in that case. Otherwise
+// the disk is created from scratch, formatted by the ISO on first boot, and
+// can be turned into a base image with SaveBaseImage.
+func MakeQcow2DiskImage(d *drivers.BaseDriver, boot2dockerURL string, diskSize int, baseImage string) (bool, error) {
+	b2dutils := mcnutils.NewB2dUtils(d.StorePath)
+	if err := b2dutils.CopyIsoToMachineDir(boot2dockerURL, d.MachineName); err != nil {
+		return false, errors.Wrap(err, "Error copying ISO to machine dir")
+	}
+	diskPath := Qcow2DiskPath(d)
+
+	fromBase := false
+	if info, err := QemuImgInfo(baseImage); err == nil && info.VirtualSize <= int64(diskSize*1000000) {
+		fromBase = true
+	}
+
+	if fromBase {
+		log.Infof("Creating disk from base image %s...", baseImage)
+		if err := copyFile(baseImageKeyPath(baseImage), d.GetSSHKeyPath(), 0600); err != nil {
+			return false, errors.Wrap(err, "copying base image ssh key")
+		}
+		if err := copyFile(baseImageKeyPath(baseImage)+".pub", publicSSHKeyPath(d), 0644); err != nil {
+			return false, errors.Wrap(err, "copying base image ssh key")
+		}
+		if err := CreateQcow2Disk(diskPath, diskSize, baseImage); err != nil {
+			return false, err
+		}
+	} else {
+		log.Info("Creating ssh key...")
+		if err := ssh.GenerateSSHKey(d.GetSSHKeyPath()); err != nil {
+			return false, err
+		}
+		log.Info("Creating qcow2 disk image...")
+		rawPath := diskPath + ".raw"
+		if err := createRawDiskImage(publicSSHKeyPath(d), rawPath, diskSize); err != nil {
+			return false, err
+		}
+		defer os.Remove(rawPath)
+		// Converting skips the unwritten, sparse part of the raw image
+		if _, err := qemuImg("convert", "-f", DiskFormatRaw, "-O", DiskFormatQcow2, rawPath, diskPath); err != nil {
+			return false, err
+		}
+	}
+	return fromBase, fixPermissions(d.ResolveStorePath("."))
+}
+
+// RotateSSHKey replaces the SSH key of a running machine, which it shares
+// with its base image, by a key of its own. The key is installed in the
+// userdata tarball which the ISO extracts into the home directory on boot,
+// so the shared key stops working on the machine.
+func RotateSSHKey(d drivers.Driver, base *drivers.BaseDriver) error {
+	log.Info("Creating ssh key...")
+	keyPath := base.GetSSHKeyPath() + ".new"
+	os.Remove(keyPath)
+	os.Remove(keyPath + ".pub")
+	if err := ssh.GenerateSSHKey(keyPath); err != nil {
+		return errors.Wrap(err, "generating ssh key")
+	}
+	tarBuf, err := mcnutils.MakeDiskImage(keyPath + ".pub")
+	if err != nil {
+		return errors.Wrap(err, "creating userdata tarball")
+	}
+	if _, err := drivers.RunSSHCommandFromDriver(d, installUserdataCmd(tarBuf.Bytes())); err != nil {
+		return errors.Wrap(err, "installing ssh key")
+	}
+	if err := os.Rename(keyPath+".pub", publicSSHKeyPath(base)); err != nil {
+		return err
+	}
+	return os.Rename(keyPath, base.GetSSHKeyPath())
+}
+
+// installUserdataCmd replaces the userdata tarball of a machine, and
+// extracts it as the ISO does on boot
+func installUserdataCmd(tarball []byte) string {
+	return fmt.Sprintf(`set -e
+echo %s | base64 -d | sudo tee /var/lib/boot2docker/userdata.tar >/dev/null
+sudo tar xf /var/lib/boot2docker/userdata.tar -C /home/docker/
+sudo chown -R docker:docker /home/docker/.ssh
+sudo rm -f '/home/docker/boot2docker, please format-me'`, base64.StdEncoding.EncodeToString(tarball))
+}
+
+// Qcow2DiskPath returns the path of a machine's qcow2 disk.
+func Qcow2DiskPath(d *drivers.BaseDriver) string {
+	return filepath.Join(d.ResolveStorePath("."), d.GetMachineName()+".qcow2")
+}
+
+// SaveBaseImage turns the disk of a freshly booted, stopped machine into a
+// base image for later machines, then recreates the machine's disk on top of
+// it so that it doesn't keep a second copy of the data.
+func SaveBaseImage(d *drivers.BaseDriver, baseImage string) error {
+	diskPath := Qcow2DiskPath(d)
+	if err := os.MkdirAll(filepath.Dir(baseImage), 0755); err != nil {
+		return err
+	}
+	// Write to a temporary file first, since another machine may be
+	// preparing the same base image
+	tmp := baseImage + ".tmp"
+	if _, err := qemuImg("convert", "-O", DiskFormatQcow2, diskPath, tmp); err != nil {
+		return err
+	}
+	if err := copyFile(d.GetSSHKeyPath(), baseImageKeyPath(baseImage), 0600); err != nil {
+		return err
+	}
+	if err := copyFile(publicSSHKeyPath(d), baseImageKeyPath(baseImage)+".pub", 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, baseImage); err != nil {
+		return err
+	}
+
+	info, err := QemuImgInfo(diskPath)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(diskPath); err != nil {
+		return err
+	}
+	return CreateQcow2Disk(diskPath, int(info.VirtualSize/1000000), baseImage)
+}
+
+// RemoveUnusedBaseImages deletes the base images in cacheDir that aren't in
+// the backing chain of any qcow2 disk under machinesDir, ignoring the disks of
+// the machine being removed in excludeDir.
+func RemoveUnusedBaseImages(cacheDir, machinesDir, excludeDir string) error {
+	bases, err := filepath.Glob(filepath.Join(cacheDir, "*.qcow2"))
+	if err != nil || len(bases) == 0 {
+		return err
+	}
+	disks, err := filepath.Glob(filepath.Join(machinesDir, "*", "*.qcow2"))
+	if err != nil {
+		return err
+	}
+	used := map[string]bool{}
+	for _, disk := range disks {
+		if filepath.Dir(disk) == filepath.Clean(excludeDir) {
+			continue
+		}
+		chain, err := BackingChain(disk)
+		if err != nil {
+			// Keep every base image rather than risk breaking a machine
+			return errors.Wrapf(err, "reading backing chain of %s", disk)
+		}
+		for _, b := range chain {
+			used[filepath.Clean(b)] = true
+		}
+	}
+	for _, base := range bases {
+		if used[filepath.Clean(base)] {
+			continue
+		}
+		log.Infof("Removing unused base image %s", base)
+		for _, p := range []string{base, baseImageKeyPath(base), baseImageKeyPath(base) + ".pub"} {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseImageInfo(t *testing.T) {
+	out := `{
+    "virtual-size": 20000000000,
+    "filename": "/home/user/.minikube/machines/minikube/minikube.qcow2",
+    "format": "qcow2",
+    "backing-filename": "/home/user/.minikube/cache/kvm/minikube-v0.26.0-0a1b2c3d.qcow2",
+    "backing-filename-format": "qcow2"
+}`
+	info, err := parseImageInfo([]byte(out))
+	if err != nil {
+		t.Fatalf("Error parsing image info: %s", err)
+	}
+	if info.Format != DiskFormatQcow2 {
+		t.Errorf("Expected format qcow2, got %s", info.Format)
+	}
+	if info.VirtualSize != 20000000000 {
+		t.Errorf("Expected virtual size 20000000000, got %d", info.VirtualSize)
+	}
+	if info.FullBackingFilename != "/home/user/.minikube/cache/kvm/minikube-v0.26.0-0a1b2c3d.qcow2" {
+		t.Errorf("Unexpected backing file %s", info.FullBackingFilename)
+	}
+}
+
+func TestBaseImagePath(t *testing.T) {
+	p := BaseImagePath("/cache", "https://storage.googleapis.com/minikube/iso/minikube-v0.26.0.iso")
+	if filepath.Dir(p) != "/cache" || !strings.HasPrefix(filepath.Base(p), "minikube-v0.26.0-") || !strings.HasSuffix(p, ".qcow2") {
+		t.Errorf("Unexpected base image path %s", p)
+	}
+	if p == BaseImagePath("/cache", "file:///tmp/minikube-v0.26.0.iso") {
+		t.Errorf("Expected ISOs with different URLs to get different base images")
+	}
+}
+
+func TestInstallUserdataCmd(t *testing.T) {
+	tarball := []byte("boot2docker, please format-me\x00.ssh/authorized_keys")
+	cmd := installUserdataCmd(tarball)
+	lines := strings.Split(cmd, "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[1], "echo ") {
+		t.Fatalf("Unexpected command:\n%s", cmd)
+	}
+	encoded := strings.Fields(lines[1])[1]
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("Error decoding the tarball: %s", err)
+	}
+	if !bytes.Equal(decoded, tarball) {
+		t.Errorf("Expected the tarball %q, got %q", tarball, decoded)
+	}
+	if !strings.Contains(cmd, "tee /var/lib/boot2docker/userdata.tar") {
+		t.Errorf("Expected the tarball to replace the persisted userdata:\n%s", cmd)
+	}
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	driverName      = "qemu"
+	isoFilename     = "boot2docker.iso"
+	pidFileName     = "qemu.pid"
+	monitorFileName = "monitor.sock"
+	logFileName     = "qemu.log"
+
+	// DefaultBinary is the qemu binary launched when none is configured
+	DefaultBinary = "qemu-system-x86_64"
+
+	// GuestIP is the address of the VM on the user-mode network. The VM is
+	// only reachable from the host through the forwarded ports.
+	GuestIP = "10.0.2.15"
+
+	// localhost is the host address the forwarded ports listen on
+	localhost = "127.0.0.1"
+
+	guestSSHPort       = 22
+	guestAPIServerPort = util.APIServerPort
+	guestDockerPort    = 2376
+)
+
+// Driver runs the minikube ISO with qemu directly, without libvirt, using
+// user-mode networking with host port forwards.
+type Driver struct {
+	*drivers.BaseDriver
+	*pkgdrivers.CommonDriver
+
+	// The qemu binary to launch
+	Binary string
+
+	// How much memory, in MB, to allocate to the VM
+	Memory int
+
+	// How many cpus to allocate to the VM
+	CPU int
+
+	// The size of the disk to be created for the VM, in MB
+	DiskSize int
+
+	// A file or network URI to fetch the minikube ISO
+	Boot2DockerURL string
+
+	// The host ports forwarded to the apiserver and docker in the VM. The SSH
+	// port is BaseDriver.SSHPort.
+	APIServerPort int
+	DockerPort    int
+}
+
+func NewDriver(hostName, storePath string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: hostName,
+			StorePath:   storePath,
+			SSHUser:     "docker",
+		},
+		CommonDriver: &pkgdrivers.CommonDriver{},
+		Binary:       DefaultBinary,
+	}
+}
+
+// PreCreateCheck checks that the qemu binary can be found
+func (d *Driver) PreCreateCheck() error {
+	if _, err := exec.LookPath(d.Binary); err != nil {
+		return errors.Wrapf(err, "%s cannot be found on the path. Install qemu to use the qemu driver", d.Binary)
+	}
+	return nil
+}
+
+func (d *Driver) Create() error {
+	if err := pkgdrivers.MakeDiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize); err != nil {
+		return errors.Wrap(err, "making disk image")
+	}
+
+	ports, err := freePorts(3)
+	if err != nil {
+		return errors.Wrap(err, "finding free ports to forward")
+	}
+	d.SSHPort, d.APIServerPort, d.DockerPort = ports[0], ports[1], ports[2]
+
+	return d.Start()
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	return driverName
+}
+
+// GetIP returns the address the VM is reachable on, which is the host itself
+func (d *Driver) GetIP() (string, error) {
+	return localhost, nil
+}
+
+// GetSSHHostname returns hostname for use with ssh
+func (d *Driver) GetSSHHostname() (string, error) {
+	return localhost, nil
+}
+
+// GetURL returns a Docker compatible host URL for connecting to this host
+func (d *Driver) GetURL() (string, error) {
+	return fmt.Sprintf("tcp://%s:%d", localhost, d.DockerPort), nil
+}
+
+// GetState returns the state that the host is in (running, stopped, etc)
+func (d *Driver) GetState() (state.State, error) {
+	pid := d.getPid()
+	if pid == 0 {
+		return state.Stopped, nil
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return state.Error, err
+	}
+
+	// Sending a signal of 0 can be used to check the existence of a process.
+	if err := p.Signal(syscall.Signal(0)); err != nil {
+		return state.Stopped, nil
+	}
+	return state.Running, nil
+}
+
+// Start a host
+func (d *Driver) Start() error {
+	kvm := kvmAvailable()
+	if !kvm {
+		log.Warn("/dev/kvm is not available, the VM will be emulated and run slowly")
+	}
+	if err := d.launch(kvm); err != nil {
+		return err
+	}
+
+	log.Info("Waiting for SSH to be available...")
+	if err := drivers.WaitForSSH(d); err != nil {
+		return errors.Wrap(err, "SSH not available after waiting")
+	}
+	return nil
+}
+
+// launch starts qemu in the background and waits for it to write its pidfile
+func (d *Driver) launch(kvm bool) error {
+	os.Remove(d.ResolveStorePath(pidFileName))
+	cmd := exec.Command(d.Binary, d.qemuArgs(kvm)...)
+	log.Debugf("Launching %s %s", d.Binary, strings.Join(cmd.Args[1:], " "))
+	// qemu forks into the background once the VM is set up
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "starting qemu: %s", out)
+	}
+	if d.getPid() == 0 {
+		return errors.Errorf("qemu did not write its pidfile %s", d.ResolveStorePath(pidFileName))
+	}
+	return nil
+}
+
+func (d *Driver) qemuArgs(kvm bool) []string {
+	args := []string{
+		"-name", d.MachineName,
+		"-m", strconv.Itoa(d.Memory),
+		"-smp", strconv.Itoa(d.CPU),
+		"-boot", "d",
+		"-cdrom", d.ResolveStorePath(isoFilename),
+		"-drive", fmt.Sprintf("file=%s,format=raw,if=virtio", pkgdrivers.GetDiskPath(d.BaseDriver)),
+		"-netdev", fmt.Sprintf("user,id=net0,hostfwd=tcp:%s:%d-:%d,hostfwd=tcp:%s:%d-:%d,hostfwd=tcp:%s:%d-:%d",
+			localhost, d.SSHPort, guestSSHPort,
+			localhost, d.APIServerPort, guestAPIServerPort,
+			localhost, d.DockerPort, guestDockerPort),
+		"-device", "virtio-net-pci,netdev=net0",
+		"-device", "virtio-rng-pci",
+		"-monitor", fmt.Sprintf("unix:%s,server,nowait", d.ResolveStorePath(monitorFileName)),
+		"-serial", fmt.Sprintf("file:%s", d.ResolveStorePath(logFileName)),
+		"-display", "none",
+		"-pidfile", d.ResolveStorePath(pidFileName),
+		"-daemonize",
+	}
+	if kvm {
+		args = append(args, "-enable-kvm", "-cpu", "host")
+	}
+	return args
+}
+
+// Stop a host gracefully, by asking the guest to power down through the
+// qemu monitor
+func (d *Driver) Stop() error {
+	s, err := d.GetState()
+	if err != nil {
+		return err
+	}
+	if s != state.Running {
+		return nil
+	}
+
+	if err := d.sendMonitorCommand("system_powerdown"); err != nil {
+		log.Warnf("Unable to power down VM, terminating it: %s", err)
+		return d.sendSignal(syscall.SIGTERM)
+	}
+	for i := 0; i < 60; i++ {
+		if s, _ := d.GetState(); s != state.Running {
+			return nil
+		}
+		time.Sleep(1 * time.Second)
+	}
+	log.Warn("VM did not power down after 60 seconds, terminating it")
+	return d.sendSignal(syscall.SIGTERM)
+}
+
+// Kill stops a host forcefully
+func (d *Driver) Kill() error {
+	return d.sendSignal(syscall.SIGKILL)
+}
+
+func (d *Driver) Restart() error {
+	return pkgdrivers.Restart(d)
+}
+
+// Remove a host
+func (d *Driver) Remove() error {
+	s, err := d.GetState()
+	if err != nil || s == state.Error {
+		log.Infof("Error checking machine status: %s, assuming it has been removed already", err)
+	}
+	if s == state.Running {
+		if err := d.Kill(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Driver) sendMonitorCommand(command string) error {
+	conn, err := net.DialTimeout("unix", d.ResolveStorePath(monitorFileName), 5*time.Second)
+	if err != nil {
+		return errors.Wrap(err, "connecting to qemu monitor")
+	}
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "%s\n", command)
+	return err
+}
+
+func (d *Driver) sendSignal(s os.Signal) error {
+	pid := d.getPid()
+	if pid == 0 {
+		return nil
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	return proc.Signal(s)
+}
+
+func (d *Driver) getPid() int {
+	pidPath := d.ResolveStorePath(pidFileName)
+
+	data, err := ioutil.ReadFile(pidPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("Error reading pid file: %s", err)
+		}
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		log.Warnf("Error parsing pid file: %s", err)
+		return 0
+	}
+
+	return pid
+}
+
+func kvmAvailable() bool {
+	f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// freePorts returns n distinct free ports on localhost
+func freePorts(n int) ([]int, error) {
+	var ports []int
+	for i := 0; i < n; i++ {
+		l, err := net.Listen("tcp", net.JoinHostPort(localhost, "0"))
+		if err != nil {
+			return nil, err
+		}
+		// Keep the listeners open until all ports are picked, so that the
+		// same port isn't returned twice
+		defer l.Close()
+		ports = append(ports, l.Addr().(*net.TCPAddr).Port)
+	}
+	return ports, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+// fakeQemu records its arguments next to the pidfile it is given and writes
+// the pid of the test process into it, so the VM looks like it's running.
+const fakeQemu = `#!/bin/sh
+while [ $# -gt 0 ]; do
+	echo "$1" >> "%[1]s"
+	if [ "$1" = "-pidfile" ]; then
+		echo %[2]d > "$2"
+	fi
+	shift
+done
+`
+
+func newTestDriver(t *testing.T, dir string) *Driver {
+	d := NewDriver("minikube", dir)
+	d.Memory = 2048
+	d.CPU = 2
+	d.SSHPort, d.APIServerPort, d.DockerPort = 2222, 18443, 12376
+
+	d.Binary = filepath.Join(dir, "qemu-system-x86_64")
+	script := fmt.Sprintf(fakeQemu, filepath.Join(dir, "args"), os.Getpid())
+	if err := ioutil.WriteFile(d.Binary, []byte(script), 0755); err != nil {
+		t.Fatalf("Error writing fake qemu: %s", err)
+	}
+	if err := os.MkdirAll(d.ResolveStorePath("."), 0755); err != nil {
+		t.Fatalf("Error creating machine dir: %s", err)
+	}
+	return d
+}
+
+func TestQemuArgs(t *testing.T) {
+	d := NewDriver("minikube", "/store")
+	d.Memory = 2048
+	d.CPU = 2
+	d.SSHPort, d.APIServerPort, d.DockerPort = 2222, 18443, 12376
+
+	for _, kvm := range []bool{false, true} {
+		args := strings.Join(d.qemuArgs(kvm), " ")
+		for _, expected := range []string{
+			"-m 2048",
+			"-smp 2",
+			"-cdrom /store/machines/minikube/boot2docker.iso",
+			"-drive file=/store/machines/minikube/minikube.rawdisk,format=raw,if=virtio",
+			"-netdev user,id=net0,hostfwd=tcp:127.0.0.1:2222-:22,hostfwd=tcp:127.0.0.1:18443-:8443,hostfwd=tcp:127.0.0.1:12376-:2376",
+			"-pidfile /store/machines/minikube/qemu.pid",
+			"-monitor unix:/store/machines/minikube/monitor.sock,server,nowait",
+			"-daemonize",
+		} {
+			if !strings.Contains(args, expected) {
+				t.Errorf("Expected %q in qemu args: %s", expected, args)
+			}
+		}
+		if hasKvm := strings.Contains(args, "-enable-kvm"); hasKvm != kvm {
+			t.Errorf("Expected -enable-kvm to be %t, got args: %s", kvm, args)
+		}
+	}
+}
+
+func TestLaunch(t *testing.T) {
+	dir := tests.MakeTempDir()
+	defer os.RemoveAll(dir)
+	d := newTestDriver(t, dir)
+
+	if s, err := d.GetState(); err != nil || s != state.Stopped {
+		t.Fatalf("Expected stopped state before launch, got %s, %v", s, err)
+	}
+	if err := d.launch(false); err != nil {
+		t.Fatalf("Error launching fake qemu: %s", err)
+	}
+
+	if pid := d.getPid(); pid != os.Getpid() {
+		t.Errorf("Expected pid %d, got %d", os.Getpid(), pid)
+	}
+	if s, err := d.GetState(); err != nil || s != state.Running {
+		t.Errorf("Expected running state after launch, got %s, %v", s, err)
+	}
+
+	out, err := ioutil.ReadFile(filepath.Join(dir, "args"))
+	if err != nil {
+		t.Fatalf("Error reading fake qemu args: %s", err)
+	}
+	got := strings.Split(strings.TrimSpace(string(out)), "\n")
+	expected := d.qemuArgs(false)
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected qemu to be run with %v, got %v", expected, got)
+	}
+}
+
+func TestLaunchWithoutPidfile(t *testing.T) {
+	dir := tests.MakeTempDir()
+	defer os.RemoveAll(dir)
+	d := newTestDriver(t, dir)
+	if err := ioutil.WriteFile(d.Binary, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatalf("Error writing fake qemu: %s", err)
+	}
+
+	if err := d.launch(false); err == nil {
+		t.Errorf("Expected an error when qemu doesn't write its pidfile")
+	}
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// DefaultReadyTimeout is how long to wait for an addon's pods to be ready
+// after applying it.
+const DefaultReadyTimeout = 3 * time.Minute
+
+// AddonLabel is set on every object applied for an addon, so that objects
+// removed from the addon's manifests can be pruned.
+const AddonLabel = "kubernetes.io/minikube-addons"
+
+// addonManagerModeLabel is the addon-manager label whose EnsureExists value
+// marks objects which are created, but never updated
+const addonManagerModeLabel = "addonmanager.kubernetes.io/mode"
+
+// Applier creates, updates and deletes addon objects directly through the
+// apiserver, rather than waiting for the addon-manager to reconcile the
+// addons directory in the VM.
+type Applier struct {
+	mapper meta.RESTMapper
+	pool   dynamic.ClientPool
+	pods   corev1.PodsGetter
+}
+
+// NewApplier returns an Applier for the cluster described by config.
+func NewApplier(config *rest.Config) (*Applier, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating discovery client")
+	}
+	resources, err := discovery.GetAPIGroupResources(dc)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting api resources")
+	}
+	mapper := discovery.NewRESTMapper(resources, meta.InterfacesForUnstructured)
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating clientset")
+	}
+	return &Applier{
+		mapper: mapper,
+		pool:   dynamic.NewClientPool(config, mapper, dynamic.LegacyAPIPathResolverFunc),
+		pods:   client.CoreV1(),
+	}, nil
+}
+
+// Apply creates or updates the objects in an addon's manifests, then deletes
+// any objects previously applied for the addon that are no longer in them.
+func (a *Applier) Apply(addon *assets.Addon) error {
+	objs, err := addonObjects(addon)
+	if err != nil {
+		return err
+	}
+
+	kinds := map[schema.GroupVersionKind]bool{}
+	applied := map[string]bool{}
+	for _, obj := range objs {
+		if err := a.defaultNamespace(obj); err != nil {
+			return err
+		}
+		if err := a.apply(obj); err != nil {
+			return errors.Wrapf(err, "applying %s %s", obj.GetKind(), obj.GetName())
+		}
+		kinds[obj.GroupVersionKind()] = true
+		applied[objectKey(obj)] = true
+	}
+
+	for gvk := range kinds {
+		if err := a.prune(addon.Name(), gvk, applied); err != nil {
+			return errors.Wrapf(err, "pruning %s objects", gvk.Kind)
+		}
+	}
+	return nil
+}
+
+// Delete deletes the objects in an addon's manifests.
+func (a *Applier) Delete(addon *assets.Addon) error {
+	objs, err := addonObjects(addon)
+	if err != nil {
+		return err
+	}
+	// Delete in reverse order, so that namespaces and RBAC objects outlive
+	// the workloads using them
+	for i := len(objs) - 1; i >= 0; i-- {
+		obj := objs[i]
+		if err := a.defaultNamespace(obj); err != nil {
+			return err
+		}
+		rc, err := a.resourceClient(obj.GroupVersionKind(), obj.GetNamespace())
+		if err != nil {
+			return err
+		}
+		if err := rc.Delete(obj.GetName(), deleteOptions()); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "deleting %s %s", obj.GetKind(), obj.GetName())
+		}
+	}
+	return nil
+}
+
+// WaitForReady waits for the addon's pods to be running and ready. It returns
+// early with an error if the pods are failing.
+func (a *Applier) WaitForReady(addon *assets.Addon, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		health, ok, err := CheckHealth(a.pods, addon)
+		if err != nil {
+			return err
+		}
+		if !ok || health.Status == Running {
+			return nil
+		}
+		if health.Status == Failing {
+			return errors.Errorf("addon %s is failing: %s", addon.Name(), health.Reason)
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out waiting for addon %s to be ready: %s", addon.Name(), health.Reason)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// apply creates the object, or patches it like kubectl apply does: the
+// manifest is recorded in the last-applied annotation, so that the fields
+// removed from it since the last apply are removed from the live object too.
+func (a *Applier) apply(obj *unstructured.Unstructured) error {
+	rc, err := a.resourceClient(obj.GroupVersionKind(), obj.GetNamespace())
+	if err != nil {
+		return err
+	}
+	modified, err := setLastApplied(obj)
+	if err != nil {
+		return errors.Wrap(err, "setting last applied configuration")
+	}
+	live, err := rc.Get(obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = rc.Create(obj)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if ensureExists(obj) {
+		return nil
+	}
+	current, err := live.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	original := []byte(live.GetAnnotations()[v1.LastAppliedConfigAnnotation])
+	patch, patchType, err := threeWayPatch(obj.GroupVersionKind(), original, modified, current)
+	if err != nil {
+		return errors.Wrap(err, "creating patch")
+	}
+	if string(patch) == "{}" {
+		return nil
+	}
+	_, err = rc.Patch(obj.GetName(), patchType, patch)
+	return err
+}
+
+// setLastApplied records the object's configuration in its last-applied
+// annotation, and returns the configuration including the annotation.
+func setLastApplied(obj *unstructured.Unstructured) ([]byte, error) {
+	original, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[v1.LastAppliedConfigAnnotation] = string(original)
+	obj.SetAnnotations(annotations)
+	return obj.MarshalJSON()
+}
+
+// threeWayPatch returns the patch updating the current object to the
+// modified configuration, which also deletes the fields of the original
+// configuration missing from the modified one. Kinds known to client-go get
+// a strategic merge patch, so that lists such as containers and env are
+// merged by key. Other kinds, such as custom resources, get a merge patch.
+func threeWayPatch(gvk schema.GroupVersionKind, original, modified, current []byte) ([]byte, types.PatchType, error) {
+	versioned, err := scheme.Scheme.New(gvk)
+	if runtime.IsNotRegisteredError(err) {
+		patch, err := threeWayMergePatch(original, modified, current)
+		return patch, types.MergePatchType, err
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	lookup, err := strategicpatch.NewPatchMetaFromStruct(versioned)
+	if err != nil {
+		return nil, "", err
+	}
+	patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, current, lookup, true)
+	return patch, types.StrategicMergePatchType, err
+}
+
+// threeWayMergePatch is the JSON merge patch of the changes from current to
+// modified, plus the deletions from original to modified.
+func threeWayMergePatch(original, modified, current []byte) ([]byte, error) {
+	if len(original) == 0 {
+		original = []byte("{}")
+	}
+	deletions, err := filteredMergePatch(original, modified, true)
+	if err != nil {
+		return nil, err
+	}
+	delta, err := filteredMergePatch(current, modified, false)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.MergeMergePatches(deletions, delta)
+}
+
+// filteredMergePatch returns the merge patch from a to b, keeping either
+// only its deletions or only its changes and additions.
+func filteredMergePatch(a, b []byte, deletions bool) ([]byte, error) {
+	data, err := jsonpatch.CreateMergePatch(a, b)
+	if err != nil {
+		return nil, err
+	}
+	var patch map[string]interface{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, err
+	}
+	filterNulls(patch, deletions)
+	return json.Marshal(patch)
+}
+
+// filterNulls keeps only the null fields of a merge patch, which delete
+// fields, or removes them
+func filterNulls(patch map[string]interface{}, keep bool) {
+	for k, v := range patch {
+		switch t := v.(type) {
+		case nil:
+			if !keep {
+				delete(patch, k)
+			}
+		case map[string]interface{}:
+			filterNulls(t, keep)
+			if len(t) == 0 {
+				delete(patch, k)
+			}
+		default:
+			if keep {
+				delete(patch, k)
+			}
+		}
+	}
+}
+
+func (a *Applier) prune(addonName string, gvk schema.GroupVersionKind, applied map[string]bool) error {
+	rc, err := a.resourceClient(gvk, metav1.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	list, err := rc.List(metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", AddonLabel, addonName)})
+	if err != nil {
+		return err
+	}
+	items, ok := list.(*unstructured.UnstructuredList)
+	if !ok {
+		return errors.Errorf("unexpected list type %T", list)
+	}
+	for i := range items.Items {
+		obj := &items.Items[i]
+		obj.SetGroupVersionKind(gvk)
+		if applied[objectKey(obj)] {
+			continue
+		}
+		glog.Infof("Pruning %s %s/%s from addon %s", gvk.Kind, obj.GetNamespace(), obj.GetName(), addonName)
+		nrc, err := a.resourceClient(gvk, obj.GetNamespace())
+		if err != nil {
+			return err
+		}
+		if err := nrc.Delete(obj.GetName(), deleteOptions()); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Applier) resourceClient(gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error) {
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "mapping %s", gvk)
+	}
+	client, err := a.pool.ClientForGroupVersionKind(gvk)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting client for %s", gvk)
+	}
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if !namespaced {
+		namespace = ""
+	}
+	resource := &metav1.APIResource{Name: mapping.Resource, Namespaced: namespaced}
+	return client.Resource(resource, namespace), nil
+}
+
+// defaultNamespace sets the default namespace on namespaced objects that
+// don't specify one, as kubectl does.
+func (a *Applier) defaultNamespace(obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return errors.Wrapf(err, "mapping %s", gvk)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace && obj.GetNamespace() == "" {
+		obj.SetNamespace(metav1.NamespaceDefault)
+	}
+	return nil
+}
+
+// ensureExists returns whether the object is only created, like the
+// addon-manager does, e.g. so that persistent volume claims aren't resized
+func ensureExists(obj *unstructured.Unstructured) bool {
+	return obj.GetLabels()[addonManagerModeLabel] == "EnsureExists"
+}
+
+func deleteOptions() *metav1.DeleteOptions {
+	policy := metav1.DeletePropagationBackground
+	return &metav1.DeleteOptions{PropagationPolicy: &policy}
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GroupVersionKind().GroupKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// addonObjects renders an addon's manifests and decodes the objects in them.
+// Only assets destined for the addons directory are included, static pod
+// manifests are left to the kubelet.
+func addonObjects(addon *assets.Addon) ([]*unstructured.Unstructured, error) {
+	files, err := addon.RenderAssets()
+	if err != nil {
+		return nil, err
+	}
+	var objs []*unstructured.Unstructured
+	for _, f := range files {
+		if f.GetTargetDir() != constants.AddonsPath {
+			continue
+		}
+		fileObjs, err := decodeObjects(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding %s", f.GetAssetName())
+		}
+		objs = append(objs, fileObjs...)
+	}
+	for _, obj := range objs {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[AddonLabel] = addon.Name()
+		obj.SetLabels(labels)
+	}
+	return objs, nil
+}
+
+// decodeObjects decodes the YAML or JSON documents in r.
+func decodeObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	d := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := d.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				return objs, nil
+			}
+			return nil, err
+		}
+		// Skip empty documents, such as those containing only comments
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, errors.New("object is missing kind or name")
+		}
+		objs = append(objs, obj)
+	}
+}
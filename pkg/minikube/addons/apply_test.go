@@ -0,0 +1,204 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+const testManifests = `# A comment only document
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: hello
+  namespace: kube-system
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+  namespace: kube-system
+`
+
+func TestDecodeObjects(t *testing.T) {
+	objs, err := decodeObjects(strings.NewReader(testManifests))
+	if err != nil {
+		t.Fatalf("Error decoding objects: %s", err)
+	}
+	if len(objs) != 2 {
+		t.Fatalf("Expected 2 objects, got %d", len(objs))
+	}
+	if objs[0].GetKind() != "Service" || objs[1].GetKind() != "Deployment" {
+		t.Errorf("Unexpected kinds: %s, %s", objs[0].GetKind(), objs[1].GetKind())
+	}
+	if objs[1].GroupVersionKind().Group != "apps" {
+		t.Errorf("Expected group apps, got %s", objs[1].GroupVersionKind().Group)
+	}
+
+	if _, err := decodeObjects(strings.NewReader("apiVersion: v1\nkind: Service\n")); err == nil {
+		t.Errorf("Expected error decoding object without a name")
+	}
+}
+
+func TestEnsureExists(t *testing.T) {
+	objs, err := decodeObjects(strings.NewReader(`apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: storage
+  labels:
+    addonmanager.kubernetes.io/mode: EnsureExists
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: hello
+  labels:
+    addonmanager.kubernetes.io/mode: Reconcile
+`))
+	if err != nil {
+		t.Fatalf("Error decoding objects: %s", err)
+	}
+	if !ensureExists(objs[0]) {
+		t.Errorf("Expected the claim to only be created")
+	}
+	if ensureExists(objs[1]) {
+		t.Errorf("Expected the service to be reconciled")
+	}
+}
+
+func TestThreeWayPatch(t *testing.T) {
+	tests := []struct {
+		description string
+		gvk         schema.GroupVersionKind
+		original    string
+		modified    string
+		current     string
+		expected    string
+		patchType   types.PatchType
+	}{
+		{
+			description: "removed container arg, env var and port",
+			gvk:         schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			original:    `{"spec":{"template":{"spec":{"containers":[{"name":"c","args":["--a","--b"],"env":[{"name":"A","value":"1"},{"name":"B","value":"2"}],"ports":[{"containerPort":80},{"containerPort":443}]}]}}}}`,
+			modified:    `{"spec":{"template":{"spec":{"containers":[{"name":"c","args":["--a"],"env":[{"name":"A","value":"1"}],"ports":[{"containerPort":80}]}]}}}}`,
+			current:     `{"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"c","args":["--a","--b"],"env":[{"name":"A","value":"1"},{"name":"B","value":"2"}],"ports":[{"containerPort":80},{"containerPort":443}],"imagePullPolicy":"IfNotPresent"}]}}},"status":{"replicas":1}}`,
+			expected:    `{"spec":{"template":{"spec":{"$setElementOrder/containers":[{"name":"c"}],"containers":[{"$setElementOrder/env":[{"name":"A"}],"$setElementOrder/ports":[{"containerPort":80}],"args":["--a"],"env":[{"$patch":"delete","name":"B"}],"name":"c","ports":[{"$patch":"delete","containerPort":443}]}]}}}}`,
+			patchType:   types.StrategicMergePatchType,
+		},
+		{
+			description: "unchanged",
+			gvk:         schema.GroupVersionKind{Version: "v1", Kind: "Service"},
+			original:    `{"spec":{"ports":[{"port":80}]}}`,
+			modified:    `{"spec":{"ports":[{"port":80}]}}`,
+			current:     `{"spec":{"clusterIP":"10.0.0.1","ports":[{"port":80,"protocol":"TCP"}]}}`,
+			expected:    `{}`,
+			patchType:   types.StrategicMergePatchType,
+		},
+		{
+			description: "custom resource",
+			gvk:         schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"},
+			original:    `{"spec":{"a":1,"b":2}}`,
+			modified:    `{"spec":{"a":3}}`,
+			current:     `{"spec":{"a":1,"b":2,"c":4},"status":{"ok":true}}`,
+			expected:    `{"spec":{"a":3,"b":null}}`,
+			patchType:   types.MergePatchType,
+		},
+		{
+			description: "created before the last applied annotation",
+			gvk:         schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"},
+			modified:    `{"spec":{"a":3}}`,
+			current:     `{"spec":{"a":1,"b":2}}`,
+			expected:    `{"spec":{"a":3}}`,
+			patchType:   types.MergePatchType,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			patch, patchType, err := threeWayPatch(test.gvk, []byte(test.original), []byte(test.modified), []byte(test.current))
+			if err != nil {
+				t.Fatalf("Error creating patch: %s", err)
+			}
+			if patchType != test.patchType {
+				t.Errorf("Expected a %s patch, got %s", test.patchType, patchType)
+			}
+			if string(patch) != test.expected {
+				t.Errorf("Expected patch:\n%s\ngot:\n%s", test.expected, patch)
+			}
+		})
+	}
+}
+
+func TestSetLastApplied(t *testing.T) {
+	objs, err := decodeObjects(strings.NewReader(testManifests))
+	if err != nil {
+		t.Fatalf("Error decoding objects: %s", err)
+	}
+	if _, err := setLastApplied(objs[0]); err != nil {
+		t.Fatalf("Error setting last applied configuration: %s", err)
+	}
+	expected := `{"apiVersion":"v1","kind":"Service","metadata":{"name":"hello","namespace":"kube-system"},"spec":{"ports":[{"port":80}]}}` + "\n"
+	if actual := objs[0].GetAnnotations()[v1.LastAppliedConfigAnnotation]; actual != expected {
+		t.Errorf("Expected last applied configuration:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestPendingChanges(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	if err := QueueChange("minikube", "ingress", true); err != nil {
+		t.Fatalf("Error queueing change: %s", err)
+	}
+	if err := QueueChange("minikube", "dashboard", false); err != nil {
+		t.Fatalf("Error queueing change: %s", err)
+	}
+	if err := QueueChange("minikube", "ingress", false); err != nil {
+		t.Fatalf("Error queueing change: %s", err)
+	}
+
+	changes, err := PendingChanges("minikube")
+	if err != nil {
+		t.Fatalf("Error reading pending changes: %s", err)
+	}
+	expected := map[string]bool{"ingress": false, "dashboard": false}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, changes)
+	}
+
+	if err := ClearPendingChanges("minikube"); err != nil {
+		t.Fatalf("Error clearing pending changes: %s", err)
+	}
+	changes, err = PendingChanges("minikube")
+	if err != nil {
+		t.Fatalf("Error reading pending changes: %s", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected no pending changes, got %v", changes)
+	}
+}
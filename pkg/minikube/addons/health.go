@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+// HealthStatus summarizes the state of the workloads created by an addon.
+type HealthStatus string
+
+const (
+	Running     HealthStatus = "Running"
+	Pending     HealthStatus = "Pending"
+	Failing     HealthStatus = "Failing"
+	NotDeployed HealthStatus = "Not deployed"
+)
+
+// Health is the health of an addon's workloads, with a short reason.
+type Health struct {
+	Status HealthStatus `json:"status"`
+	Reason string       `json:"reason,omitempty"`
+}
+
+// failingReasons are container waiting reasons that won't resolve on their own.
+var failingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// CheckHealth returns the health of the pods created by an addon. It returns
+// false if the addon doesn't run any pods.
+func CheckHealth(client corev1.PodsGetter, addon *assets.Addon) (Health, bool, error) {
+	selector, ok := addon.PodSelector()
+	if !ok {
+		return Health{}, false, nil
+	}
+	pods, err := client.Pods(metav1.NamespaceAll).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return Health{}, true, errors.Wrapf(err, "listing pods for addon %s", addon.Name())
+	}
+	return healthFromPods(pods.Items), true, nil
+}
+
+func healthFromPods(pods []v1.Pod) Health {
+	if len(pods) == 0 {
+		return Health{Status: NotDeployed, Reason: "no pods found"}
+	}
+
+	// Pods being deleted, e.g. replaced by a rollout, are counted neither
+	// as ready nor as expected
+	ready, live := 0, 0
+	var pending *Health
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		live++
+		if pod.Status.Phase == v1.PodFailed {
+			return Health{Status: Failing, Reason: fmt.Sprintf("%s: %s", pod.Name, podReason(pod, "Failed"))}
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if w := cs.State.Waiting; w != nil && failingReasons[w.Reason] {
+				return Health{Status: Failing, Reason: fmt.Sprintf("%s: %s", pod.Name, w.Reason)}
+			}
+		}
+		if podReady(pod) {
+			ready++
+			continue
+		}
+		if pending == nil {
+			pending = &Health{Status: Pending, Reason: fmt.Sprintf("%s: %s", pod.Name, podReason(pod, string(pod.Status.Phase)))}
+		}
+	}
+	if pending != nil {
+		return *pending
+	}
+	if ready == 0 {
+		return Health{Status: NotDeployed, Reason: "pods are terminating"}
+	}
+	return Health{Status: Running, Reason: fmt.Sprintf("%d/%d pods ready", ready, live)}
+}
+
+func podReady(pod v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podReason returns the most specific reason available for a pod not being ready.
+func podReason(pod v1.Pod, fallback string) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if w := cs.State.Waiting; w != nil && w.Reason != "" {
+			return w.Reason
+		}
+		if t := cs.State.Terminated; t != nil && t.Reason != "" {
+			return t.Reason
+		}
+	}
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Status != v1.ConditionTrue && c.Reason != "" {
+			return c.Reason
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func pod(name string, phase v1.PodPhase, ready bool, waiting string) v1.Pod {
+	p := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+	readyStatus := v1.ConditionFalse
+	if ready {
+		readyStatus = v1.ConditionTrue
+	}
+	p.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: readyStatus}}
+	if waiting != "" {
+		p.Status.ContainerStatuses = []v1.ContainerStatus{
+			{State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: waiting}}},
+		}
+	}
+	return p
+}
+
+func terminating(p v1.Pod) v1.Pod {
+	now := metav1.Now()
+	p.DeletionTimestamp = &now
+	return p
+}
+
+func TestHealthFromPods(t *testing.T) {
+	var tests = []struct {
+		description string
+		pods        []v1.Pod
+		status      HealthStatus
+		reason      string
+	}{
+		{
+			description: "no pods",
+			status:      NotDeployed,
+			reason:      "no pods found",
+		},
+		{
+			description: "all ready",
+			pods: []v1.Pod{
+				pod("a", v1.PodRunning, true, ""),
+				pod("b", v1.PodRunning, true, ""),
+			},
+			status: Running,
+			reason: "2/2 pods ready",
+		},
+		{
+			description: "rolled out",
+			pods: []v1.Pod{
+				pod("a", v1.PodRunning, true, ""),
+				terminating(pod("b", v1.PodRunning, true, "")),
+				terminating(pod("c", v1.PodRunning, false, "")),
+			},
+			status: Running,
+			reason: "1/1 pods ready",
+		},
+		{
+			description: "terminating",
+			pods: []v1.Pod{
+				terminating(pod("a", v1.PodRunning, true, "")),
+			},
+			status: NotDeployed,
+			reason: "pods are terminating",
+		},
+		{
+			description: "creating",
+			pods: []v1.Pod{
+				pod("a", v1.PodRunning, true, ""),
+				pod("b", v1.PodPending, false, "ContainerCreating"),
+			},
+			status: Pending,
+			reason: "b: ContainerCreating",
+		},
+		{
+			description: "crash looping",
+			pods: []v1.Pod{
+				pod("a", v1.PodPending, false, "ContainerCreating"),
+				pod("b", v1.PodRunning, false, "CrashLoopBackOff"),
+			},
+			status: Failing,
+			reason: "b: CrashLoopBackOff",
+		},
+		{
+			description: "image pull",
+			pods: []v1.Pod{
+				pod("a", v1.PodPending, false, "ImagePullBackOff"),
+			},
+			status: Failing,
+			reason: "a: ImagePullBackOff",
+		},
+		{
+			description: "failed",
+			pods: []v1.Pod{
+				pod("a", v1.PodFailed, false, ""),
+			},
+			status: Failing,
+			reason: "a: Failed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			h := healthFromPods(test.pods)
+			if h.Status != test.status {
+				t.Errorf("Expected status %s, got %s", test.status, h.Status)
+			}
+			if h.Reason != test.reason {
+				t.Errorf("Expected reason %q, got %q", test.reason, h.Reason)
+			}
+		})
+	}
+}
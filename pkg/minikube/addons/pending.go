@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// pendingChangesFile returns the file that addon changes made while the
+// profile's VM is stopped are queued in.
+func pendingChangesFile(profile string) string {
+	return filepath.Join(filepath.Dir(constants.GetProfileFile(profile)), "pending-addons.json")
+}
+
+// QueueChange records that an addon was enabled or disabled while the VM was
+// stopped, so the change can be applied on the next start.
+func QueueChange(profile, addonName string, enable bool) error {
+	changes, err := PendingChanges(profile)
+	if err != nil {
+		return err
+	}
+	changes[addonName] = enable
+	data, err := json.MarshalIndent(changes, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "encoding pending addon changes")
+	}
+	file := pendingChangesFile(profile)
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0600)
+}
+
+// PendingChanges returns the queued addon changes for a profile, mapping
+// addon names to whether they should be enabled.
+func PendingChanges(profile string) (map[string]bool, error) {
+	changes := map[string]bool{}
+	data, err := ioutil.ReadFile(pendingChangesFile(profile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return changes, nil
+		}
+		return nil, errors.Wrap(err, "reading pending addon changes")
+	}
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil, errors.Wrap(err, "decoding pending addon changes")
+	}
+	return changes, nil
+}
+
+// ClearPendingChanges removes the queued addon changes for a profile.
+func ClearPendingChanges(profile string) error {
+	if err := os.Remove(pendingChangesFile(profile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
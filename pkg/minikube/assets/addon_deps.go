@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RequiredAddons returns the addons that must be enabled for the named addon
+// to work, including transitive dependencies, in the order they should be
+// enabled. The named addon itself is not included.
+func RequiredAddons(name string) ([]string, error) {
+	var order []string
+	visited := map[string]bool{}
+	var visit func(n string, path []string) error
+	visit = func(n string, path []string) error {
+		for _, p := range path {
+			if p == n {
+				return errors.Errorf("addon dependency cycle: %s", strings.Join(append(path, n), " -> "))
+			}
+		}
+		if visited[n] {
+			return nil
+		}
+		a, ok := Addons[n]
+		if !ok {
+			if len(path) == 0 {
+				return errors.Errorf("unknown addon %s", n)
+			}
+			return errors.Errorf("addon %s requires unknown addon %s", path[len(path)-1], n)
+		}
+		next := append(append([]string{}, path...), n)
+		for _, r := range a.Requires {
+			if err := visit(r, next); err != nil {
+				return err
+			}
+		}
+		visited[n] = true
+		order = append(order, n)
+		return nil
+	}
+	if err := visit(name, nil); err != nil {
+		return nil, err
+	}
+	// The named addon is always visited last
+	return order[:len(order)-1], nil
+}
+
+// ConflictingAddons returns the addons that can't be enabled alongside the
+// named addon. Conflicts are symmetric, so an addon declaring a conflict with
+// the named addon is included.
+func ConflictingAddons(name string) []string {
+	conflicts := map[string]bool{}
+	if a, ok := Addons[name]; ok {
+		for _, c := range a.Conflicts {
+			conflicts[c] = true
+		}
+	}
+	for n, a := range Addons {
+		for _, c := range a.Conflicts {
+			if c == name {
+				conflicts[n] = true
+			}
+		}
+	}
+	return sortedKeys(conflicts)
+}
+
+// RequiredBy returns the addons that directly require the named addon.
+func RequiredBy(name string) []string {
+	dependents := map[string]bool{}
+	for n, a := range Addons {
+		for _, r := range a.Requires {
+			if r == name {
+				dependents[n] = true
+			}
+		}
+	}
+	return sortedKeys(dependents)
+}
+
+// EnabledAddons filters names down to the addons that are currently enabled.
+func EnabledAddons(names []string) ([]string, error) {
+	var enabled []string
+	for _, n := range names {
+		a, ok := Addons[n]
+		if !ok {
+			continue
+		}
+		isEnabled, err := a.IsEnabled()
+		if err != nil {
+			return nil, errors.Wrapf(err, "checking if %s is enabled", n)
+		}
+		if isEnabled {
+			enabled = append(enabled, n)
+		}
+	}
+	return enabled, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
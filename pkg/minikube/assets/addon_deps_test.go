@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequiredAddons(t *testing.T) {
+	Addons["test-a"] = NewAddon(nil, false, "test-a").withRequires("test-b", "storage-provisioner")
+	Addons["test-b"] = NewAddon(nil, false, "test-b").withRequires("default-storageclass")
+	Addons["test-cycle"] = NewAddon(nil, false, "test-cycle").withRequires("test-cycle-dep")
+	Addons["test-cycle-dep"] = NewAddon(nil, false, "test-cycle-dep").withRequires("test-cycle")
+	Addons["test-unknown"] = NewAddon(nil, false, "test-unknown").withRequires("does-not-exist")
+	defer func() {
+		for _, n := range []string{"test-a", "test-b", "test-cycle", "test-cycle-dep", "test-unknown"} {
+			delete(Addons, n)
+		}
+	}()
+
+	var tests = []struct {
+		addon    string
+		expected []string
+		err      bool
+	}{
+		{addon: "dashboard", expected: []string{}},
+		{addon: "default-storageclass", expected: []string{"storage-provisioner"}},
+		{addon: "test-a", expected: []string{"storage-provisioner", "default-storageclass", "test-b"}},
+		{addon: "test-cycle", err: true},
+		{addon: "test-unknown", err: true},
+		{addon: "does-not-exist", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.addon, func(t *testing.T) {
+			required, err := RequiredAddons(test.addon)
+			if test.err {
+				if err == nil {
+					t.Fatalf("Expected error, got %v", required)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(required, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, required)
+			}
+		})
+	}
+}
+
+func TestConflictingAddons(t *testing.T) {
+	if c := ConflictingAddons("kube-dns"); !reflect.DeepEqual(c, []string{"coredns"}) {
+		t.Errorf("Expected kube-dns to conflict with coredns, got %v", c)
+	}
+
+	// Conflicts only declared on one side are still symmetric
+	Addons["test-conflict"] = NewAddon(nil, false, "test-conflict").withConflicts("registry")
+	defer delete(Addons, "test-conflict")
+	if c := ConflictingAddons("registry"); !reflect.DeepEqual(c, []string{"test-conflict"}) {
+		t.Errorf("Expected registry to conflict with test-conflict, got %v", c)
+	}
+}
+
+func TestRequiredBy(t *testing.T) {
+	if r := RequiredBy("storage-provisioner"); !reflect.DeepEqual(r, []string{"default-storageclass"}) {
+		t.Errorf("Expected storage-provisioner to be required by default-storageclass, got %v", r)
+	}
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
+	"github.com/golang/glog"
+	download "github.com/jimmidyson/go-download"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// AddonManifestFile is the name of the manifest at the root of an addon package.
+const AddonManifestFile = "addon.yaml"
+
+// TemplateSuffix marks the assets of an addon package which are rendered as
+// Go templates with the addon's parameters. Other assets are copied as is.
+const TemplateSuffix = ".tmpl"
+
+var validAddonName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// AddonManifest describes a third-party addon package.
+type AddonManifest struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	DefaultEnabled bool   `json:"defaultEnabled"`
+	// KubernetesVersion is an optional semver range, such as ">=1.9.0".
+	KubernetesVersion string   `json:"kubernetesVersion"`
+	Requires          []string `json:"requires"`
+	Conflicts         []string `json:"conflicts"`
+	// Selector matches the pods run by the addon, for health reporting.
+	Selector   string               `json:"selector"`
+	Parameters map[string]string    `json:"parameters"`
+	Assets     []AddonManifestAsset `json:"assets"`
+}
+
+// AddonManifestAsset is a file in an addon package to be copied to the VM.
+type AddonManifestAsset struct {
+	// Path is the location of the file, relative to the package root.
+	Path string `json:"path"`
+	// TargetDir defaults to the addons directory watched by the addon-manager.
+	TargetDir string `json:"targetDir"`
+	// TargetName defaults to the base name of Path, without TemplateSuffix.
+	TargetName string `json:"targetName"`
+	// Permissions defaults to 0640.
+	Permissions string `json:"permissions"`
+}
+
+// InstalledAddonsDir returns the directory that installed addon packages are stored in.
+func InstalledAddonsDir() string {
+	return constants.MakeMiniPath("addon-packages")
+}
+
+// ReadAddonManifest reads and validates the manifest of the addon package in dir.
+func ReadAddonManifest(dir string) (*AddonManifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, AddonManifestFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading addon manifest")
+	}
+	m := &AddonManifest{}
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrap(err, "parsing addon manifest")
+	}
+	if err := m.validate(dir); err != nil {
+		return nil, errors.Wrapf(err, "invalid addon manifest %s", filepath.Join(dir, AddonManifestFile))
+	}
+	return m, nil
+}
+
+func (m *AddonManifest) validate(dir string) error {
+	if !validAddonName.MatchString(m.Name) {
+		return errors.Errorf("invalid addon name %q, must consist of lower case alphanumeric characters or '-'", m.Name)
+	}
+	if m.KubernetesVersion != "" {
+		if _, err := semver.ParseRange(m.KubernetesVersion); err != nil {
+			return errors.Wrapf(err, "invalid kubernetesVersion %q", m.KubernetesVersion)
+		}
+	}
+	if len(m.Assets) == 0 {
+		return errors.New("no assets listed")
+	}
+	for _, a := range m.Assets {
+		p := filepath.Clean(filepath.FromSlash(a.Path))
+		if filepath.IsAbs(p) || strings.HasPrefix(p, "..") {
+			return errors.Errorf("asset path %q must be relative to the package", a.Path)
+		}
+		if _, err := os.Stat(filepath.Join(dir, p)); err != nil {
+			return errors.Wrapf(err, "asset %q", a.Path)
+		}
+	}
+	return nil
+}
+
+// LoadAddonPackage loads the addon package in dir.
+func LoadAddonPackage(dir string) (*Addon, error) {
+	m, err := ReadAddonManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	a := &Addon{
+		Description:       m.Description,
+		KubernetesVersion: m.KubernetesVersion,
+		Requires:          m.Requires,
+		Conflicts:         m.Conflicts,
+		Selector:          m.Selector,
+		Parameters:        m.Parameters,
+		enabled:           m.DefaultEnabled,
+		addonName:         m.Name,
+	}
+	for _, ma := range m.Assets {
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(ma.Path)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading asset %s", ma.Path)
+		}
+		targetDir := ma.TargetDir
+		if targetDir == "" {
+			targetDir = constants.AddonsPath
+		}
+		isTemplate := strings.HasSuffix(ma.Path, TemplateSuffix)
+		targetName := ma.TargetName
+		if targetName == "" {
+			targetName = strings.TrimSuffix(path.Base(ma.Path), TemplateSuffix)
+		}
+		perms := ma.Permissions
+		if perms == "" {
+			perms = "0640"
+		}
+		asset := NewMemoryAsset(data, targetDir, targetName, perms)
+		asset.AssetName = filepath.Join(dir, filepath.FromSlash(ma.Path))
+		if isTemplate {
+			a.Assets = append(a.Assets, asset)
+		} else {
+			a.Assets = append(a.Assets, &plainAsset{asset})
+		}
+	}
+	return a, nil
+}
+
+// plainAsset is an asset of an addon package which isn't a template, so that
+// manifests containing {{ }}, such as Helm annotations, are copied unchanged.
+type plainAsset struct {
+	*MemoryAsset
+}
+
+// Evaluate returns a copy of the asset, ignoring the data.
+func (p *plainAsset) Evaluate(_ interface{}) (*MemoryAsset, error) {
+	a := NewMemoryAsset(p.data, p.TargetDir, p.TargetName, p.Permissions)
+	a.AssetName = p.AssetName
+	return a, nil
+}
+
+// LoadInstalledAddons adds the addon packages installed under
+// InstalledAddonsDir to Addons. Packages that fail to load are skipped.
+func LoadInstalledAddons() error {
+	dirs, err := ioutil.ReadDir(InstalledAddonsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "reading installed addons")
+	}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		a, err := LoadAddonPackage(filepath.Join(InstalledAddonsDir(), d.Name()))
+		if err != nil {
+			glog.Warningf("Skipping addon package %s: %s", d.Name(), err)
+			continue
+		}
+		if _, ok := Addons[a.Name()]; ok {
+			glog.Warningf("Skipping addon package %s: an addon named %s already exists", d.Name(), a.Name())
+			continue
+		}
+		Addons[a.Name()] = a
+	}
+	return nil
+}
+
+// InstallAddonPackage installs an addon package from a local directory, a
+// local or remote tarball, or a git repository, and adds it to Addons.
+func InstallAddonPackage(source string) (*Addon, error) {
+	tmpDir, err := ioutil.TempDir("", "minikube-addon")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dir, err := fetchAddonPackage(source, tmpDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching addon package %s", source)
+	}
+	m, err := ReadAddonManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := Addons[m.Name]; ok && !isInstalledAddon(existing) {
+		return nil, errors.Errorf("addon %s conflicts with a built-in addon", m.Name)
+	}
+
+	target := filepath.Join(InstalledAddonsDir(), m.Name)
+	if err := os.RemoveAll(target); err != nil {
+		return nil, errors.Wrapf(err, "removing previous install of %s", m.Name)
+	}
+	if err := copyDir(dir, target); err != nil {
+		return nil, errors.Wrapf(err, "copying addon package to %s", target)
+	}
+	a, err := LoadAddonPackage(target)
+	if err != nil {
+		return nil, err
+	}
+	Addons[a.Name()] = a
+	return a, nil
+}
+
+func isInstalledAddon(a *Addon) bool {
+	_, err := os.Stat(filepath.Join(InstalledAddonsDir(), a.Name(), AddonManifestFile))
+	return err == nil
+}
+
+// fetchAddonPackage retrieves source into tmpDir if needed, returning the
+// directory containing the package manifest.
+func fetchAddonPackage(source, tmpDir string) (string, error) {
+	switch {
+	case isGitSource(source):
+		dir := filepath.Join(tmpDir, "git")
+		url := strings.TrimPrefix(source, "git+")
+		if out, err := exec.Command("git", "clone", "--depth", "1", url, dir).CombinedOutput(); err != nil {
+			return "", errors.Wrapf(err, "cloning %s: %s", url, out)
+		}
+		return dir, nil
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		archive := filepath.Join(tmpDir, path.Base(source))
+		if err := download.ToFile(source, archive, download.FileOptions{Mkdirs: download.MkdirAll}); err != nil {
+			return "", errors.Wrapf(err, "downloading %s", source)
+		}
+		return extractAddonArchive(archive, filepath.Join(tmpDir, "archive"))
+	}
+
+	fi, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+	if fi.IsDir() {
+		return source, nil
+	}
+	return extractAddonArchive(source, filepath.Join(tmpDir, "archive"))
+}
+
+func isGitSource(source string) bool {
+	return strings.HasPrefix(source, "git+") ||
+		strings.HasPrefix(source, "git://") ||
+		strings.HasPrefix(source, "git@") ||
+		strings.HasSuffix(source, ".git")
+}
+
+// extractAddonArchive extracts a tar or gzipped tar archive into dir. The
+// manifest may be at the root of the archive or inside a single top level
+// directory.
+func extractAddonArchive(archive, dir string) (string, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(archive, ".gz") || strings.HasSuffix(archive, ".tgz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return "", errors.Wrap(err, "reading gzip archive")
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "reading tar archive")
+		}
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || strings.HasPrefix(name, "..") {
+			return "", errors.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return "", err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return "", err
+			}
+			if err := writeFile(target, tr, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, AddonManifestFile)); err == nil {
+		return dir, nil
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return "", errors.Errorf("%s not found in archive", AddonManifestFile)
+}
+
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return writeFile(target, f, info.Mode().Perm())
+	})
+}
+
+func writeFile(target string, r io.Reader, perm os.FileMode) error {
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
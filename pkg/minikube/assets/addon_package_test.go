@@ -0,0 +1,198 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+const testManifest = `name: hello
+description: Says hello
+kubernetesVersion: ">=1.9.0"
+parameters:
+  image: hello:1.0
+assets:
+- path: manifests/hello-dp.yaml.tmpl
+- path: manifests/hello-svc.yaml
+  targetName: svc.yaml
+- path: manifests/hello-cm.yaml
+`
+
+var testPackageFiles = map[string]string{
+	AddonManifestFile:              testManifest,
+	"manifests/hello-dp.yaml.tmpl": "image: {{ .image }}\n",
+	"manifests/hello-svc.yaml":     "kind: Service\n",
+	"manifests/hello-cm.yaml":      "greeting: '{{ .Values.greeting }}'\n",
+}
+
+func writeTestPackage(t *testing.T, dir string) {
+	for name, contents := range testPackageFiles {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("Error creating dir: %s", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatalf("Error writing file: %s", err)
+		}
+	}
+}
+
+func TestLoadAddonPackage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addon")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	writeTestPackage(t, dir)
+
+	a, err := LoadAddonPackage(dir)
+	if err != nil {
+		t.Fatalf("Error loading addon package: %s", err)
+	}
+	if a.Name() != "hello" {
+		t.Errorf("Expected name hello, got %s", a.Name())
+	}
+	if len(a.Assets) != 3 {
+		t.Fatalf("Expected 3 assets, got %d", len(a.Assets))
+	}
+	if a.Assets[0].GetTargetDir() != constants.AddonsPath || a.Assets[0].GetTargetName() != "hello-dp.yaml" {
+		t.Errorf("Unexpected target for asset: %s/%s", a.Assets[0].GetTargetDir(), a.Assets[0].GetTargetName())
+	}
+	if a.Assets[1].GetTargetName() != "svc.yaml" {
+		t.Errorf("Expected target name svc.yaml, got %s", a.Assets[1].GetTargetName())
+	}
+
+	m, err := a.Assets[0].Evaluate(a.Parameters)
+	if err != nil {
+		t.Fatalf("Error evaluating asset: %s", err)
+	}
+	if string(m.data) != "image: hello:1.0\n" {
+		t.Errorf("Unexpected rendered asset: %q", string(m.data))
+	}
+
+	// Assets without the template suffix are copied as is
+	m, err = a.Assets[2].Evaluate(a.Parameters)
+	if err != nil {
+		t.Fatalf("Error evaluating a literal asset: %s", err)
+	}
+	if string(m.data) != testPackageFiles["manifests/hello-cm.yaml"] || m.GetTargetName() != "hello-cm.yaml" {
+		t.Errorf("Unexpected literal asset %s: %q", m.GetTargetName(), string(m.data))
+	}
+
+	if !a.SupportsKubernetesVersion("v1.10.0") {
+		t.Errorf("Expected addon to support v1.10.0")
+	}
+	if a.SupportsKubernetesVersion("v1.8.0") {
+		t.Errorf("Expected addon to not support v1.8.0")
+	}
+}
+
+func TestReadAddonManifestInvalid(t *testing.T) {
+	var tests = []struct {
+		description string
+		manifest    string
+	}{
+		{
+			description: "invalid name",
+			manifest:    "name: Hello World\nassets:\n- path: manifests/hello-dp.yaml\n",
+		},
+		{
+			description: "no assets",
+			manifest:    "name: hello\n",
+		},
+		{
+			description: "missing asset",
+			manifest:    "name: hello\nassets:\n- path: manifests/missing.yaml\n",
+		},
+		{
+			description: "asset outside package",
+			manifest:    "name: hello\nassets:\n- path: ../hello-dp.yaml\n",
+		},
+		{
+			description: "invalid version range",
+			manifest:    "name: hello\nkubernetesVersion: latest\nassets:\n- path: manifests/hello-dp.yaml\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "addon")
+			if err != nil {
+				t.Fatalf("Error creating temp dir: %s", err)
+			}
+			defer os.RemoveAll(dir)
+			writeTestPackage(t, dir)
+			if err := ioutil.WriteFile(filepath.Join(dir, AddonManifestFile), []byte(test.manifest), 0644); err != nil {
+				t.Fatalf("Error writing manifest: %s", err)
+			}
+			if _, err := ReadAddonManifest(dir); err == nil {
+				t.Errorf("Expected error reading manifest")
+			}
+		})
+	}
+}
+
+func TestExtractAddonArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addon")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive := filepath.Join(dir, "hello.tar.gz")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatalf("Error creating archive: %s", err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, contents := range testPackageFiles {
+		hdr := &tar.Header{
+			Name:     "hello/" + name,
+			Mode:     0644,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("Error writing header: %s", err)
+		}
+		if _, err := tw.Write([]byte(contents)); err != nil {
+			t.Fatalf("Error writing contents: %s", err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	pkgDir, err := extractAddonArchive(archive, filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatalf("Error extracting archive: %s", err)
+	}
+	if pkgDir != filepath.Join(dir, "out", "hello") {
+		t.Errorf("Unexpected package dir: %s", pkgDir)
+	}
+	if _, err := ReadAddonManifest(pkgDir); err != nil {
+		t.Errorf("Error reading extracted manifest: %s", err)
+	}
+}
@@ -20,29 +20,216 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/blang/semver"
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/util"
+	minikubeVersion "k8s.io/minikube/pkg/version"
 )
 
 type Addon struct {
-	Assets    []*BinDataAsset
-	enabled   bool
-	addonName string
+	Assets []TemplateAsset
+	// Description is a short summary of the addon, set for installed addon
+	// packages.
+	Description string
+	// KubernetesVersion is an optional semver range, such as ">=1.9.0", that
+	// the cluster's Kubernetes version must satisfy for the addon to be used.
+	KubernetesVersion string
+	// Requires lists the addons that must be enabled for this addon to work.
+	Requires []string
+	// Conflicts lists the addons that must not be enabled alongside this addon.
+	Conflicts []string
+	// Selector is the label selector matching the pods run by the addon. It
+	// defaults to the kubernetes.io/minikube-addons label.
+	Selector string
+	// Parameters are the template parameters accepted by the addon's assets,
+	// mapped to their default values.
+	Parameters  map[string]string
+	enabled     bool
+	addonName   string
+	noWorkloads bool
 }
 
 func NewAddon(assets []*BinDataAsset, enabled bool, addonName string) *Addon {
 	a := &Addon{
-		Assets:    assets,
 		enabled:   enabled,
 		addonName: addonName,
 	}
+	for _, asset := range assets {
+		a.Assets = append(a.Assets, asset)
+	}
 	return a
 }
 
+// Name returns the name of the addon.
+func (a *Addon) Name() string {
+	return a.addonName
+}
+
+// SupportsKubernetesVersion returns whether the addon can be used with the
+// given Kubernetes version. Versions that can't be parsed, such as localkube
+// URIs, are assumed to be supported.
+func (a *Addon) SupportsKubernetesVersion(version string) bool {
+	if a.KubernetesVersion == "" {
+		return true
+	}
+	v, err := semver.Make(strings.TrimPrefix(version, minikubeVersion.VersionPrefix))
+	if err != nil {
+		glog.Infof("Unable to parse kubernetes version %q, skipping version check for addon %s", version, a.addonName)
+		return true
+	}
+	r, err := semver.ParseRange(a.KubernetesVersion)
+	if err != nil {
+		glog.Warningf("Invalid kubernetes version range %q for addon %s: %s", a.KubernetesVersion, a.addonName, err)
+		return false
+	}
+	return r(v)
+}
+
+// withParameters declares the template parameters, and their defaults, that
+// can be set for the addon.
+func (a *Addon) withParameters(params map[string]string) *Addon {
+	a.Parameters = params
+	return a
+}
+
+// withRequires declares the addons that this addon depends on.
+func (a *Addon) withRequires(names ...string) *Addon {
+	a.Requires = names
+	return a
+}
+
+// withConflicts declares the addons that can't be enabled alongside this addon.
+func (a *Addon) withConflicts(names ...string) *Addon {
+	a.Conflicts = names
+	return a
+}
+
+// withSelector sets the label selector matching the addon's pods.
+func (a *Addon) withSelector(selector string) *Addon {
+	a.Selector = selector
+	return a
+}
+
+// withoutWorkloads marks an addon that doesn't run any pods, so it has no
+// health to report.
+func (a *Addon) withoutWorkloads() *Addon {
+	a.noWorkloads = true
+	return a
+}
+
+// PodSelector returns the label selector matching the pods run by the addon,
+// or false if the addon doesn't run any pods.
+func (a *Addon) PodSelector() (string, bool) {
+	if a.noWorkloads {
+		return "", false
+	}
+	if a.Selector != "" {
+		return a.Selector, true
+	}
+	return "kubernetes.io/minikube-addons=" + a.addonName, true
+}
+
+// GetParameters returns the addon's default parameters overridden by any
+// values stored for the addon in the minikube config.
+func (a *Addon) GetParameters() (map[string]string, error) {
+	stored, err := config.GetAddonParameters(a.addonName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting parameters for addon %s", a.addonName)
+	}
+	params := make(map[string]string)
+	for k, v := range a.Parameters {
+		params[k] = v
+	}
+	for k, v := range stored {
+		if _, ok := a.Parameters[k]; !ok {
+			glog.Warningf("Ignoring unknown parameter %q for addon %s", k, a.addonName)
+			continue
+		}
+		params[k] = v
+	}
+	return params, nil
+}
+
+// ValidateParameters returns an error if any of the given parameters are not
+// accepted by the addon.
+func (a *Addon) ValidateParameters(params map[string]string) error {
+	for k := range params {
+		if _, ok := a.Parameters[k]; !ok {
+			return errors.Errorf("unknown parameter %q for addon %s, valid parameters are: %s", k, a.addonName, strings.Join(a.parameterNames(), ", "))
+		}
+	}
+	return nil
+}
+
+func (a *Addon) parameterNames() []string {
+	names := make([]string, 0, len(a.Parameters))
+	for k := range a.Parameters {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clusterParameters returns the template parameters derived from the cluster
+// config of the current profile, which are available to every addon.
+func clusterParameters() map[string]string {
+	var k8s config.KubernetesConfig
+	cc, err := config.Load(config.GetMachineName())
+	if err == nil {
+		k8s = cc.KubernetesConfig
+	} else if !os.IsNotExist(err) {
+		glog.Warningf("Unable to load the cluster config, using the default cluster parameters: %s", err)
+	}
+
+	domain := k8s.DNSDomain
+	if domain == "" {
+		domain = util.DefaultDNSDomain
+	}
+	serviceCIDR := k8s.ServiceCIDR
+	if serviceCIDR == "" {
+		serviceCIDR = util.DefaultServiceCIDR
+	}
+	dnsIP, err := util.GetDNSIP(serviceCIDR)
+	if err != nil {
+		glog.Warningf("Invalid service cidr %s, using the default: %s", serviceCIDR, err)
+		dnsIP, _ = util.GetDNSIP(util.DefaultServiceCIDR)
+	}
+	return map[string]string{
+		"dnsDomain": domain,
+		"dnsIP":     dnsIP.String(),
+	}
+}
+
+// RenderAssets evaluates the addon's assets with its current parameters,
+// returning files ready to be copied to the VM.
+func (a *Addon) RenderAssets() ([]CopyableFile, error) {
+	params, err := a.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range clusterParameters() {
+		if _, ok := params[k]; !ok {
+			params[k] = v
+		}
+	}
+	files := make([]CopyableFile, 0, len(a.Assets))
+	for _, asset := range a.Assets {
+		f, err := asset.Evaluate(params)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rendering addon %s", a.addonName)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
 func (a *Addon) IsEnabled() (bool, error) {
 	addonStatusText, err := config.Get(a.addonName)
 	if err == nil {
@@ -74,21 +261,24 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"dashboard-svc.yaml",
 			"0640"),
-	}, true, "dashboard"),
+	}, true, "dashboard").withSelector("app=kubernetes-dashboard").withParameters(map[string]string{
+		"image":     "k8s.gcr.io/kubernetes-dashboard-amd64:v1.8.1",
+		"extraArgs": "",
+	}),
 	"default-storageclass": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/storageclass/storageclass.yaml",
 			constants.AddonsPath,
 			"storageclass.yaml",
 			"0640"),
-	}, true, "default-storageclass"),
+	}, true, "default-storageclass").withRequires("storage-provisioner").withoutWorkloads(),
 	"storage-provisioner": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/storage-provisioner/storage-provisioner.yaml",
 			constants.AddonsPath,
 			"storage-provisioner.yaml",
 			"0640"),
-	}, true, "storage-provisioner"),
+	}, true, "storage-provisioner").withSelector("integration-test=storage-provisioner"),
 	"coredns": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/coredns/coreDNS-controller.yaml",
@@ -120,7 +310,7 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"coreDNS-clusterrole.yaml",
 			"0640"),
-	}, false, "coredns"),
+	}, false, "coredns").withConflicts("kube-dns").withSelector("k8s-app=kube-dns"),
 	"kube-dns": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/kube-dns/kube-dns-controller.yaml",
@@ -137,7 +327,7 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"kube-dns-svc.yaml",
 			"0640"),
-	}, true, "kube-dns"),
+	}, true, "kube-dns").withConflicts("coredns").withSelector("k8s-app=kube-dns"),
 	"heapster": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/heapster/influx-grafana-rc.yaml",
@@ -164,7 +354,7 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"heapster-svc.yaml",
 			"0640"),
-	}, false, "heapster"),
+	}, false, "heapster").withConflicts("metrics-server").withSelector("k8s-app in (heapster, influx-grafana)"),
 	"efk": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/efk/elasticsearch-rc.yaml",
@@ -196,7 +386,7 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"kibana-svc.yaml",
 			"0640"),
-	}, false, "efk"),
+	}, false, "efk").withSelector("k8s-app in (elasticsearch-logging, fluentd-es, kibana-logging)"),
 	"ingress": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/ingress/ingress-configmap.yaml",
@@ -213,7 +403,10 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"ingress-svc.yaml",
 			"0640"),
-	}, false, "ingress"),
+	}, false, "ingress").withSelector("app in (nginx-ingress-controller, default-http-backend)").withParameters(map[string]string{
+		"controllerImage":     "quay.io/kubernetes-ingress-controller/nginx-ingress-controller:0.14.0",
+		"defaultBackendImage": "k8s.gcr.io/defaultbackend:1.4",
+	}),
 	"metrics-server": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/metrics-server/metrics-apiservice.yaml",
@@ -230,8 +423,13 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"metrics-server-service.yaml",
 			"0640"),
-	}, false, "metrics-server"),
+	}, false, "metrics-server").withConflicts("heapster").withSelector("k8s-app=metrics-server"),
 	"registry": NewAddon([]*BinDataAsset{
+		NewBinDataAsset(
+			"deploy/addons/registry/registry-pvc.yaml",
+			constants.AddonsPath,
+			"registry-pvc.yaml",
+			"0640"),
 		NewBinDataAsset(
 			"deploy/addons/registry/registry-rc.yaml",
 			constants.AddonsPath,
@@ -242,21 +440,24 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"registry-svc.yaml",
 			"0640"),
-	}, false, "registry"),
+	}, false, "registry").withRequires("default-storageclass").withParameters(map[string]string{
+		"image":       "registry.hub.docker.com/library/registry:2.6.1",
+		"storageSize": "10Gi",
+	}),
 	"registry-creds": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/registry-creds/registry-creds-rc.yaml",
 			constants.AddonsPath,
 			"registry-creds-rc.yaml",
 			"0640"),
-	}, false, "registry-creds"),
+	}, false, "registry-creds").withSelector("name=registry-creds"),
 	"freshpod": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
 			"deploy/addons/freshpod/freshpod-rc.yaml",
 			constants.AddonsPath,
 			"freshpod-rc.yaml",
 			"0640"),
-	}, false, "freshpod"),
+	}, false, "freshpod").withSelector("k8s-app=freshpod"),
 }
 
 func AddMinikubeDirAssets(assets *[]CopyableFile) error {
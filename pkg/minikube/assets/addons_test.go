@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func renderDNSAddon(t *testing.T) string {
+	a := &Addon{
+		Assets:    []TemplateAsset{NewMemoryAsset([]byte("{{.dnsDomain}} {{.dnsIP}}"), "/etc", "dns.yaml", "0640")},
+		addonName: "test-dns",
+	}
+	files, err := a.RenderAssets()
+	if err != nil {
+		t.Fatalf("Error rendering addon: %s", err)
+	}
+	b, err := ioutil.ReadAll(files[0])
+	if err != nil {
+		t.Fatalf("Error reading rendered asset: %s", err)
+	}
+	return string(b)
+}
+
+func TestRenderClusterParameters(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	if got := renderDNSAddon(t); got != "cluster.local 10.96.0.10" {
+		t.Errorf("Expected the default cluster parameters, got %q", got)
+	}
+
+	profile := constants.GetProfileFile(config.GetMachineName())
+	if err := os.MkdirAll(filepath.Dir(profile), 0755); err != nil {
+		t.Fatalf("Error creating profile dir: %s", err)
+	}
+	cc := `{"KubernetesConfig": {"DNSDomain": "corp.example", "ServiceCIDR": "172.30.0.0/16"}}`
+	if err := ioutil.WriteFile(profile, []byte(cc), 0644); err != nil {
+		t.Fatalf("Error writing profile: %s", err)
+	}
+	if got := renderDNSAddon(t); got != "corp.example 172.30.0.10" {
+		t.Errorf("Expected the cluster parameters of the profile, got %q", got)
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
+	"text/template"
 
 	"github.com/pkg/errors"
 )
@@ -167,3 +169,43 @@ func (m *BinDataAsset) GetLength() int {
 func (m *BinDataAsset) Read(p []byte) (int, error) {
 	return m.reader.Read(p)
 }
+
+// TemplateAsset is an asset whose contents are rendered as a Go template
+// before being copied to the VM.
+type TemplateAsset interface {
+	CopyableFile
+	Evaluate(data interface{}) (*MemoryAsset, error)
+}
+
+// templateFuncs are the helper functions available to templated assets.
+var templateFuncs = template.FuncMap{
+	"split": func(s string) []string {
+		return strings.Split(s, ",")
+	},
+}
+
+// Evaluate renders the asset as a Go template with the given data and returns
+// the result as an in-memory asset with the same target.
+func (m *BinDataAsset) Evaluate(data interface{}) (*MemoryAsset, error) {
+	return m.evaluate(data)
+}
+
+// Evaluate renders the asset as a Go template with the given data and returns
+// the result as a new in-memory asset with the same target.
+func (m *MemoryAsset) Evaluate(data interface{}) (*MemoryAsset, error) {
+	return m.evaluate(data)
+}
+
+func (b *BaseAsset) evaluate(data interface{}) (*MemoryAsset, error) {
+	tmpl, err := template.New(b.AssetName).Funcs(templateFuncs).Option("missingkey=error").Parse(string(b.data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing template %s", b.AssetName)
+	}
+	buf := bytes.Buffer{}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, errors.Wrapf(err, "executing template %s", b.AssetName)
+	}
+	a := NewMemoryAsset(buf.Bytes(), b.TargetDir, b.TargetName, b.Permissions)
+	a.AssetName = b.AssetName
+	return a, nil
+}
@@ -28,9 +28,10 @@ type Bootstrapper interface {
 	StartCluster(config.KubernetesConfig) error
 	UpdateCluster(config.KubernetesConfig) error
 	RestartCluster(config.KubernetesConfig) error
-	GetClusterLogsTo(follow bool, out io.Writer) error
+	UpgradeCluster(current, target config.KubernetesConfig) error
+	GetClusterLogsTo(k8s config.KubernetesConfig, opts LogOptions, out io.Writer) error
 	SetupCerts(cfg config.KubernetesConfig) error
-	GetClusterStatus() (string, error)
+	GetClusterStatus(config.KubernetesConfig) (ClusterStatus, error)
 }
 
 const (
@@ -0,0 +1,227 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine/state"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+// Names of the components reported by GetClusterStatus
+const (
+	Kubelet           = "kubelet"
+	Localkube         = "localkube"
+	APIServer         = "apiserver"
+	Etcd              = "etcd"
+	Scheduler         = "scheduler"
+	ControllerManager = "controller-manager"
+	DNS               = "dns"
+)
+
+// healthCheckTimeout is the number of seconds a single health check may take
+const healthCheckTimeout = 5
+
+// unknownState is reported for components which could not be probed
+const unknownState = "Unknown"
+
+// ComponentStatus is the health of a single cluster component
+type ComponentStatus struct {
+	Name  string
+	State string
+	// Error is the last error reported by the component's health check
+	Error string `json:",omitempty"`
+	// Optional components do not affect the overall cluster state
+	Optional bool `json:",omitempty"`
+}
+
+// ClusterStatus is the overall state of the cluster along with the state
+// of each of its components
+type ClusterStatus struct {
+	State      string
+	Components []ComponentStatus
+}
+
+// Component returns the status of the named component
+func (s ClusterStatus) Component(name string) (ComponentStatus, bool) {
+	for _, c := range s.Components {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ComponentStatus{}, false
+}
+
+// HealthCheck describes how to probe a component from inside the VM
+type HealthCheck struct {
+	Name string
+	// Command is run on the VM, a non-zero exit status means the component is unhealthy
+	Command string
+	// Expect is the output of a healthy component, any output is accepted if empty
+	Expect string
+	// Requires is the component this one depends on; it is not probed when
+	// the required component isn't running
+	Requires string
+	// Optional components do not affect the overall cluster state
+	Optional bool
+}
+
+// stoppedOutputs are substrings of health check output which mean the
+// component is not running, rather than running but unhealthy
+var stoppedOutputs = []string{
+	state.Stopped.String(),
+	"inactive",
+	"Connection refused",
+	"Failed to connect",
+}
+
+// ControlPlaneHealthChecks returns the checks for the control plane
+// components and DNS, all of which depend on the root component, i.e. the
+// process which runs the cluster.
+func ControlPlaneHealthChecks(root string, k8s config.KubernetesConfig) []HealthCheck {
+	checks := []HealthCheck{
+		{
+			Name:     APIServer,
+			Command:  curlCommand(fmt.Sprintf("https://localhost:%d/healthz", util.APIServerPort)),
+			Expect:   "ok",
+			Requires: root,
+		},
+		{
+			// etcd is only reachable with client certificates, so rely on
+			// the apiserver's view of it
+			Name:     Etcd,
+			Command:  curlCommand(fmt.Sprintf("https://localhost:%d/healthz/etcd", util.APIServerPort)),
+			Expect:   "ok",
+			Requires: APIServer,
+		},
+		{
+			Name:     Scheduler,
+			Command:  curlCommand("http://127.0.0.1:10251/healthz"),
+			Expect:   "ok",
+			Requires: root,
+		},
+		{
+			Name:     ControllerManager,
+			Command:  curlCommand("http://127.0.0.1:10252/healthz"),
+			Expect:   "ok",
+			Requires: root,
+		},
+	}
+	return append(checks, dnsHealthCheck(k8s))
+}
+
+func curlCommand(url string) string {
+	return fmt.Sprintf("curl -sSk --max-time %d %s", healthCheckTimeout, url)
+}
+
+// dnsHealthCheck resolves the kubernetes service through the cluster DNS.
+// DNS is provided by an addon which may be disabled, so it is optional.
+func dnsHealthCheck(k8s config.KubernetesConfig) HealthCheck {
+	domain := k8s.DNSDomain
+	if domain == "" {
+		domain = util.DefaultDNSDomain
+	}
+	serviceCIDR := k8s.ServiceCIDR
+	if serviceCIDR == "" {
+		serviceCIDR = util.DefaultServiceCIDR
+	}
+	check := HealthCheck{
+		Name:     DNS,
+		Requires: APIServer,
+		Optional: true,
+	}
+	dnsIP, err := util.GetDNSIP(serviceCIDR)
+	if err != nil {
+		check.Command = fmt.Sprintf("echo %q; false", err.Error())
+		return check
+	}
+	check.Command = fmt.Sprintf("timeout %d nslookup kubernetes.default.svc.%s %s",
+		healthCheckTimeout, domain, dnsIP)
+	return check
+}
+
+// CheckHealth runs the health checks in order and aggregates them into the
+// state of the cluster. The first check is the root component: the cluster
+// is stopped when it is stopped.
+func CheckHealth(cmd CommandRunner, checks []HealthCheck) ClusterStatus {
+	status := ClusterStatus{State: state.Running.String()}
+	for _, check := range checks {
+		c := checkComponent(cmd, check, status)
+		status.Components = append(status.Components, c)
+		if c.State == state.Running.String() || check.Optional {
+			continue
+		}
+		if len(status.Components) == 1 && c.State == state.Stopped.String() {
+			status.State = state.Stopped.String()
+		} else if status.State == state.Running.String() {
+			status.State = state.Error.String()
+		}
+	}
+	return status
+}
+
+func checkComponent(cmd CommandRunner, check HealthCheck, status ClusterStatus) ComponentStatus {
+	c := ComponentStatus{Name: check.Name, Optional: check.Optional}
+	if check.Requires != "" {
+		required, ok := status.Component(check.Requires)
+		if ok && required.State != state.Running.String() {
+			c.State = unknownState
+			if required.State == state.Stopped.String() {
+				c.State = state.Stopped.String()
+			}
+			c.Error = fmt.Sprintf("%s is not running", check.Requires)
+			return c
+		}
+	}
+
+	out, err := cmd.CombinedOutput(check.Command)
+	out = strings.TrimSpace(out)
+	if err == nil && (check.Expect == "" || out == check.Expect) {
+		c.State = state.Running.String()
+		return c
+	}
+
+	c.State = state.Error.String()
+	for _, s := range stoppedOutputs {
+		if strings.Contains(out, s) {
+			c.State = state.Stopped.String()
+		}
+	}
+	switch {
+	case out != "":
+		c.Error = oneLine(out)
+	case err != nil:
+		c.Error = err.Error()
+	default:
+		c.Error = "no output"
+	}
+	return c
+}
+
+// oneLine joins the non-empty lines of multi-line command output
+func oneLine(out string) string {
+	var lines []string
+	for _, l := range strings.Split(out, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "; ")
+}
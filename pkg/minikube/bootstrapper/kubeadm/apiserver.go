@@ -0,0 +1,317 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// apiServerFilesVersion is the oldest version which the audit policy and the
+// admission configuration can be set for
+var apiServerFilesVersion = semver.MustParse("1.10.0-alpha.0")
+
+// auditLogComponent is the log component of the apiserver audit log
+const auditLogComponent = "audit"
+
+const (
+	// auditLogMaxSize is the size in megabytes at which the audit log is rotated
+	auditLogMaxSize = 100
+	// auditLogMaxBackups is the number of rotated audit logs kept
+	auditLogMaxBackups = 5
+)
+
+// auditPolicyPresets are the audit policies which can be selected by name
+var auditPolicyPresets = map[string]string{
+	// metadata logs who did what to which object, without any body
+	"metadata": `apiVersion: audit.k8s.io/v1beta1
+kind: Policy
+omitStages:
+- RequestReceived
+rules:
+- level: Metadata
+`,
+	// request also logs the bodies of requests, except for secrets and
+	// tokens, and leaves out the noise of health checks and the node
+	"request": `apiVersion: audit.k8s.io/v1beta1
+kind: Policy
+omitStages:
+- RequestReceived
+rules:
+- level: None
+  nonResourceURLs:
+  - /healthz*
+  - /version
+  - /swagger*
+- level: None
+  users:
+  - system:kube-proxy
+  verbs:
+  - watch
+- level: None
+  userGroups:
+  - system:nodes
+  verbs:
+  - get
+  - list
+  - watch
+- level: None
+  resources:
+  - group: ""
+    resources:
+    - events
+- level: Metadata
+  resources:
+  - group: ""
+    resources:
+    - secrets
+    - configmaps
+  - group: authentication.k8s.io
+    resources:
+    - tokenreviews
+- level: Request
+  verbs:
+  - get
+  - list
+  - watch
+- level: RequestResponse
+`,
+}
+
+// AuditPolicyPresets returns the sorted names of the audit policy presets
+func AuditPolicyPresets() []string {
+	var names []string
+	for name := range auditPolicyPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AuditPolicy returns the audit policy of a preset name, and whether the
+// name is a preset.
+func AuditPolicy(preset string) (string, bool) {
+	policy, ok := auditPolicyPresets[preset]
+	return policy, ok
+}
+
+// ValidateAPIServerFiles checks that the audit policy and the admission
+// configuration of the cluster are documents of the right kind, and that its
+// version can mount them into the apiserver.
+func ValidateAPIServerFiles(k8s config.KubernetesConfig) error {
+	if k8s.AuditPolicy == "" && k8s.AdmissionConfig == "" {
+		return nil
+	}
+	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing kubernetes version")
+	}
+	if version.LT(apiServerFilesVersion) {
+		return errors.Errorf("audit policies and admission configs need kubernetes v1.10 or later, got %s", k8s.KubernetesVersion)
+	}
+	if k8s.AuditPolicy != "" {
+		if err := checkKind(k8s.AuditPolicy, "Policy"); err != nil {
+			return errors.Wrap(err, "invalid audit policy")
+		}
+	}
+	if k8s.AdmissionConfig != "" {
+		if err := checkKind(k8s.AdmissionConfig, "AdmissionConfiguration"); err != nil {
+			return errors.Wrap(err, "invalid admission config")
+		}
+	}
+	return nil
+}
+
+// checkKind checks that a YAML document is of the kind
+func checkKind(doc, kind string) error {
+	var meta struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}
+	if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+		return errors.Wrap(err, "parsing yaml")
+	}
+	if meta.Kind != kind {
+		return errors.Errorf("kind must be %s, got %q", kind, meta.Kind)
+	}
+	if meta.APIVersion == "" {
+		return errors.New("apiVersion is missing")
+	}
+	return nil
+}
+
+// hostPathMount is an extra volume of a kubeadm static pod. kubeadm mounts
+// the extra volumes read-only, so the Writable ones are made writable in the
+// generated manifest.
+type hostPathMount struct {
+	Name      string
+	HostPath  string
+	MountPath string
+	Writable  bool
+}
+
+// apiServerFiles returns the apiserver flags and volumes of the audit policy
+// and the admission configuration of the cluster. The audit log is rotated by
+// the apiserver.
+func apiServerFiles(k8s config.KubernetesConfig) (map[string]string, []hostPathMount) {
+	flags := map[string]string{}
+	var volumes []hostPathMount
+	if k8s.AuditPolicy != "" {
+		flags["audit-policy-file"] = constants.AuditPolicyFile
+		flags["audit-log-path"] = constants.AuditLogFile
+		flags["audit-log-maxsize"] = fmt.Sprint(auditLogMaxSize)
+		flags["audit-log-maxbackup"] = fmt.Sprint(auditLogMaxBackups)
+		volumes = append(volumes,
+			hostPathMount{Name: "audit-policy", HostPath: path.Dir(constants.AuditPolicyFile), MountPath: path.Dir(constants.AuditPolicyFile)},
+			hostPathMount{Name: "audit-log", HostPath: path.Dir(constants.AuditLogFile), MountPath: path.Dir(constants.AuditLogFile), Writable: true},
+		)
+	}
+	if k8s.AdmissionConfig != "" {
+		flags["admission-control-config-file"] = constants.AdmissionConfigFile
+		volumes = append(volumes,
+			hostPathMount{Name: "admission-config", HostPath: path.Dir(constants.AdmissionConfigFile), MountPath: path.Dir(constants.AdmissionConfigFile)},
+		)
+	}
+	return flags, volumes
+}
+
+// makeAPIServerVolumesWritable makes the audit log volume of the cluster
+// writable in the apiserver manifest, which kubeadm init, upgrade and the
+// restart phases regenerate. The kubelet restarts the apiserver when its
+// manifest changes.
+func (k *KubeadmBootstrapper) makeAPIServerVolumesWritable(k8s config.KubernetesConfig) error {
+	if k8s.AuditPolicy == "" {
+		return nil
+	}
+	_, volumes := apiServerFiles(k8s)
+	manifest, err := k.c.CombinedOutput("sudo cat " + constants.APIServerManifest)
+	if err != nil {
+		return errors.Wrapf(err, "reading the apiserver manifest: %s", manifest)
+	}
+	patched, changed, err := writableMounts([]byte(manifest), volumes)
+	if err != nil {
+		return errors.Wrap(err, "patching the apiserver manifest")
+	}
+	if !changed {
+		return nil
+	}
+	if err := k.c.Copy(assets.NewMemoryAssetTarget(patched, constants.APIServerManifest, "0600")); err != nil {
+		return errors.Wrap(err, "writing the apiserver manifest")
+	}
+	return nil
+}
+
+// writableMounts makes the container mounts of the writable volumes
+// read-write in a static pod manifest, and returns whether any was read-only
+func writableMounts(manifest []byte, volumes []hostPathMount) ([]byte, bool, error) {
+	writable := map[string]bool{}
+	for _, v := range volumes {
+		if v.Writable {
+			writable[v.Name] = true
+		}
+	}
+	if len(writable) == 0 {
+		return manifest, false, nil
+	}
+
+	var pod v1.Pod
+	if err := yaml.Unmarshal(manifest, &pod); err != nil {
+		return nil, false, errors.Wrap(err, "parsing manifest")
+	}
+	changed := false
+	for i := range pod.Spec.Containers {
+		mounts := pod.Spec.Containers[i].VolumeMounts
+		for j := range mounts {
+			if writable[mounts[j].Name] && mounts[j].ReadOnly {
+				mounts[j].ReadOnly = false
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return manifest, false, nil
+	}
+	patched, err := yaml.Marshal(pod)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "marshalling manifest")
+	}
+	return patched, true, nil
+}
+
+// apiServerFileAssets returns the audit policy and the admission
+// configuration of the cluster, to be copied into the VM
+func apiServerFileAssets(k8s config.KubernetesConfig) []assets.CopyableFile {
+	var files []assets.CopyableFile
+	if k8s.AuditPolicy != "" {
+		files = append(files, assets.NewMemoryAssetTarget([]byte(k8s.AuditPolicy), constants.AuditPolicyFile, "0640"))
+	}
+	if k8s.AdmissionConfig != "" {
+		files = append(files, assets.NewMemoryAssetTarget([]byte(k8s.AdmissionConfig), constants.AdmissionConfigFile, "0640"))
+	}
+	return files
+}
+
+// addAPIServerArgs adds flags to the apiserver extra args, which are created
+// if no other apiserver option is set
+func addAPIServerArgs(args []ComponentExtraArgs, flags map[string]string) []ComponentExtraArgs {
+	if len(flags) == 0 {
+		return args
+	}
+	key := componentToKubeadmConfigKey[Apiserver]
+	for _, a := range args {
+		if a.Component == key {
+			for k, v := range flags {
+				a.Options[k] = v
+			}
+			return args
+		}
+	}
+	return append([]ComponentExtraArgs{{Component: key, Options: flags}}, args...)
+}
+
+// auditLogSource returns the audit log of the apiserver, which has a JSON
+// event per line
+func auditLogSource(k8s config.KubernetesConfig, opts bootstrapper.LogOptions) (bootstrapper.LogSource, error) {
+	if k8s.AuditPolicy == "" {
+		return bootstrapper.LogSource{}, errors.New("audit logging is disabled, start the cluster with --audit-policy to enable it")
+	}
+	if opts.Since > 0 {
+		return bootstrapper.LogSource{}, errors.New("the audit log can't be filtered with --since, use --tail")
+	}
+	flags := []string{"-n", "+1"}
+	if opts.Tail > 0 {
+		flags = []string{"-n", fmt.Sprint(opts.Tail)}
+	}
+	if opts.Follow {
+		flags = append(flags, "-F")
+	}
+	return bootstrapper.LogSource{
+		Component: auditLogComponent,
+		Command:   fmt.Sprintf("sudo tail %s %s", strings.Join(flags, " "), constants.AuditLogFile),
+	}, nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/api/core/v1"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestValidateAPIServerFiles(t *testing.T) {
+	tests := []struct {
+		description string
+		k8s         config.KubernetesConfig
+		shouldErr   bool
+	}{
+		{
+			description: "no files",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.9.4"},
+		},
+		{
+			description: "admission config",
+			k8s: config.KubernetesConfig{
+				KubernetesVersion: "v1.10.0",
+				AdmissionConfig:   "apiVersion: apiserver.k8s.io/v1alpha1\nkind: AdmissionConfiguration\nplugins: []\n",
+			},
+		},
+		{
+			description: "old version",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.9.4", AuditPolicy: auditPolicyPresets["metadata"]},
+			shouldErr:   true,
+		},
+		{
+			description: "wrong kind",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.10.0", AuditPolicy: "apiVersion: v1\nkind: ConfigMap\n"},
+			shouldErr:   true,
+		},
+		{
+			description: "no api version",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.10.0", AdmissionConfig: "kind: AdmissionConfiguration\n"},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid yaml",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.10.0", AuditPolicy: "kind: [Policy\n"},
+			shouldErr:   true,
+		},
+	}
+	for _, preset := range AuditPolicyPresets() {
+		policy, ok := AuditPolicy(preset)
+		if !ok {
+			t.Fatalf("Expected %s to be a preset", preset)
+		}
+		tests = append(tests, struct {
+			description string
+			k8s         config.KubernetesConfig
+			shouldErr   bool
+		}{
+			description: preset + " preset",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.10.0", AuditPolicy: policy},
+		})
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := ValidateAPIServerFiles(test.k8s)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestAddAPIServerArgs(t *testing.T) {
+	flags := map[string]string{"audit-log-path": "/var/log/audit.log"}
+
+	args := []ComponentExtraArgs{
+		{Component: "apiServerExtraArgs", Options: map[string]string{"v": "10"}},
+		{Component: "schedulerExtraArgs", Options: map[string]string{"v": "5"}},
+	}
+	expected := []ComponentExtraArgs{
+		{Component: "apiServerExtraArgs", Options: map[string]string{"v": "10", "audit-log-path": "/var/log/audit.log"}},
+		{Component: "schedulerExtraArgs", Options: map[string]string{"v": "5"}},
+	}
+	if actual := addAPIServerArgs(args, flags); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, actual)
+	}
+
+	args = []ComponentExtraArgs{{Component: "schedulerExtraArgs", Options: map[string]string{"v": "5"}}}
+	expected = []ComponentExtraArgs{
+		{Component: "apiServerExtraArgs", Options: flags},
+		{Component: "schedulerExtraArgs", Options: map[string]string{"v": "5"}},
+	}
+	if actual := addAPIServerArgs(args, flags); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, actual)
+	}
+}
+
+// kubeadmAPIServerManifest is an apiserver manifest as kubeadm v1.10 writes
+// it, with the extra volumes of the audit policy and log mounted read-only
+const kubeadmAPIServerManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-apiserver
+  namespace: kube-system
+spec:
+  containers:
+  - command:
+    - kube-apiserver
+    - --audit-log-path=/var/log/kubernetes/audit/audit.log
+    - --audit-policy-file=/etc/kubernetes/audit/policy.yaml
+    image: k8s.gcr.io/kube-apiserver-amd64:v1.10.0
+    name: kube-apiserver
+    volumeMounts:
+    - mountPath: /etc/kubernetes/pki
+      name: k8s-certs
+      readOnly: true
+    - mountPath: /etc/kubernetes/audit
+      name: audit-policy
+      readOnly: true
+    - mountPath: /var/log/kubernetes/audit
+      name: audit-log
+      readOnly: true
+  hostNetwork: true
+  volumes:
+  - hostPath:
+      path: /etc/kubernetes/pki
+      type: DirectoryOrCreate
+    name: k8s-certs
+  - hostPath:
+      path: /etc/kubernetes/audit
+      type: DirectoryOrCreate
+    name: audit-policy
+  - hostPath:
+      path: /var/log/kubernetes/audit
+      type: DirectoryOrCreate
+    name: audit-log
+`
+
+func TestWritableMounts(t *testing.T) {
+	_, volumes := apiServerFiles(config.KubernetesConfig{AuditPolicy: "kind: Policy"})
+
+	patched, changed, err := writableMounts([]byte(kubeadmAPIServerManifest), volumes)
+	if err != nil {
+		t.Fatalf("Error patching the manifest: %s", err)
+	}
+	if !changed {
+		t.Fatal("Expected the manifest to change")
+	}
+	var pod v1.Pod
+	if err := yaml.Unmarshal(patched, &pod); err != nil {
+		t.Fatalf("Error parsing the patched manifest: %s", err)
+	}
+	readOnly := map[string]bool{}
+	for _, m := range pod.Spec.Containers[0].VolumeMounts {
+		readOnly[m.Name] = m.ReadOnly
+	}
+	expected := map[string]bool{"k8s-certs": true, "audit-policy": true, "audit-log": false}
+	if !reflect.DeepEqual(readOnly, expected) {
+		t.Errorf("Expected the read-only mounts %v, got %v", expected, readOnly)
+	}
+	if len(pod.Spec.Volumes) != 3 || pod.Spec.Containers[0].Image != "k8s.gcr.io/kube-apiserver-amd64:v1.10.0" {
+		t.Errorf("Expected the rest of the manifest to be kept, got:\n%s", patched)
+	}
+
+	if _, changed, err := writableMounts(patched, volumes); err != nil || changed {
+		t.Errorf("Expected the patched manifest to be kept, got changed=%t, err=%v", changed, err)
+	}
+}
+
+func TestAuditLogSource(t *testing.T) {
+	enabled := config.KubernetesConfig{AuditPolicy: auditPolicyPresets["metadata"]}
+	tests := []struct {
+		description string
+		k8s         config.KubernetesConfig
+		opts        bootstrapper.LogOptions
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "whole log",
+			k8s:         enabled,
+			expected:    "sudo tail -n +1 /var/log/kubernetes/audit/audit.log",
+		},
+		{
+			description: "tail and follow",
+			k8s:         enabled,
+			opts:        bootstrapper.LogOptions{Tail: 20, Follow: true},
+			expected:    "sudo tail -n 20 -F /var/log/kubernetes/audit/audit.log",
+		},
+		{
+			description: "since",
+			k8s:         enabled,
+			opts:        bootstrapper.LogOptions{Since: time.Minute},
+			shouldErr:   true,
+		},
+		{
+			description: "disabled",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			s, err := auditLogSource(test.k8s, test.opts)
+			if err != nil {
+				if !test.shouldErr {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if test.shouldErr {
+				t.Fatal("Expected an error")
+			}
+			if s.Command != test.expected {
+				t.Errorf("Expected command %q, got %q", test.expected, s.Command)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/cni"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// componentConfigVersion is the first version whose kubelet reads a
+// KubeletConfiguration file and whose kubeadm takes a KubeProxyConfiguration.
+// Older versions are only configured with flags.
+var componentConfigVersion = semver.MustParse("1.10.0-alpha.0")
+
+func supportsComponentConfig(version semver.Version) bool {
+	return version.GTE(componentConfigVersion)
+}
+
+// configField is the KubeletConfiguration field of a kubelet flag
+type configField struct {
+	path  []string
+	parse func(string) (interface{}, error)
+	// flagDefault is the default of the flag when the field's default
+	// differs from it. It is written to the file, so that the kubelet
+	// behaves the same as with flags.
+	flagDefault string
+}
+
+// kubeletConfigFields are the kubelet flags set by minikube, or whose default
+// changed in v1beta1, which have a KubeletConfiguration field. They are moved
+// to the config file of kubelets reading one, the others stay flags.
+// cadvisor-port has no field in v1beta1, so it stays a flag.
+var kubeletConfigFields = map[string]configField{
+	"anonymous-auth":               {path: []string{"authentication", "anonymous", "enabled"}, parse: parseBool, flagDefault: "true"},
+	"authentication-token-webhook": {path: []string{"authentication", "webhook", "enabled"}, parse: parseBool, flagDefault: "false"},
+	"authorization-mode":           {path: []string{"authorization", "mode"}, parse: parseString, flagDefault: "AlwaysAllow"},
+	"cgroup-driver":                {path: []string{"cgroupDriver"}, parse: parseString},
+	"client-ca-file":               {path: []string{"authentication", "x509", "clientCAFile"}, parse: parseString},
+	"cluster-dns":                  {path: []string{"clusterDNS"}, parse: parseList},
+	"cluster-domain":               {path: []string{"clusterDomain"}, parse: parseString},
+	"fail-swap-on":                 {path: []string{"failSwapOn"}, parse: parseBool},
+	"pod-manifest-path":            {path: []string{"staticPodPath"}, parse: parseString},
+	"read-only-port":               {path: []string{"readOnlyPort"}, parse: parseInt, flagDefault: "10255"},
+	"runtime-request-timeout":      {path: []string{"runtimeRequestTimeout"}, parse: parseString},
+}
+
+func parseString(s string) (interface{}, error) {
+	return s, nil
+}
+
+func parseInt(s string) (interface{}, error) {
+	return strconv.Atoi(s)
+}
+
+func parseBool(s string) (interface{}, error) {
+	return strconv.ParseBool(s)
+}
+
+func parseList(s string) (interface{}, error) {
+	var l []interface{}
+	for _, v := range strings.Split(s, ",") {
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+// NewKubeletConfiguration generates the KubeletConfiguration of the cluster
+// from the kubelet options which have a field, and the flag defaults which
+// differ from the field defaults, e.g. the read-only port scraped by heapster
+// and metrics-server, and merges the kubelet config patch of the cluster into it.
+func NewKubeletConfiguration(opts map[string]string, k8s config.KubernetesConfig) (string, error) {
+	doc := map[string]interface{}{
+		"apiVersion": "kubelet.config.k8s.io/v1beta1",
+		"kind":       "KubeletConfiguration",
+	}
+	for flag, field := range kubeletConfigFields {
+		v, ok := opts[flag]
+		if !ok && field.flagDefault == "" {
+			continue
+		}
+		if !ok {
+			v = field.flagDefault
+		}
+		val, err := field.parse(v)
+		if err != nil {
+			return "", errors.Wrapf(err, "parsing kubelet option %s=%s", flag, v)
+		}
+		setField(doc, field.path, val)
+	}
+	if k8s.FeatureGates != "" {
+		gates, err := parseFeatureGates(k8s.FeatureGates)
+		if err != nil {
+			return "", err
+		}
+		doc["featureGates"] = gates
+	}
+	b, err := applyPatch(doc, k8s.KubeletConfigPatch)
+	if err != nil {
+		return "", errors.Wrap(err, "applying the kubelet config patch")
+	}
+	return string(b), nil
+}
+
+// NewKubeProxyConfiguration generates the KubeProxyConfiguration of the
+// cluster, which kubeadm defaults, with the kube-proxy config patch of the
+// cluster merged into it.
+func NewKubeProxyConfiguration(k8s config.KubernetesConfig) (string, error) {
+	doc := map[string]interface{}{
+		"apiVersion": "kubeproxy.config.k8s.io/v1alpha1",
+		"kind":       "KubeProxyConfiguration",
+	}
+	if cidr := cni.PodCIDR(k8s); cidr != "" {
+		doc["clusterCIDR"] = cidr
+	}
+	b, err := applyPatch(doc, k8s.KubeProxyConfigPatch)
+	if err != nil {
+		return "", errors.Wrap(err, "applying the kube-proxy config patch")
+	}
+	return string(b), nil
+}
+
+// ValidateConfigPatches checks that the config patches of the cluster are
+// YAML objects, and that its version is configured with config files.
+func ValidateConfigPatches(k8s config.KubernetesConfig) error {
+	if k8s.KubeletConfigPatch == "" && k8s.KubeProxyConfigPatch == "" {
+		return nil
+	}
+	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing kubernetes version")
+	}
+	if !supportsComponentConfig(version) {
+		return errors.Errorf("config patches need kubernetes v1.10 or later, configure %s clusters with --extra-config instead", k8s.KubernetesVersion)
+	}
+	for name, patch := range map[string]string{"kubelet": k8s.KubeletConfigPatch, "kube-proxy": k8s.KubeProxyConfigPatch} {
+		if _, err := applyPatch(map[string]interface{}{}, patch); err != nil {
+			return errors.Wrapf(err, "invalid %s config patch", name)
+		}
+	}
+	return nil
+}
+
+// parseFeatureGates parses the --feature-gates flag into a featureGates map
+func parseFeatureGates(featureGates string) (map[string]bool, error) {
+	gates := map[string]bool{}
+	for _, gate := range strings.Split(featureGates, ",") {
+		kv := strings.SplitN(strings.TrimSpace(gate), "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid feature gate %q, must be name=true|false", gate)
+		}
+		enabled, err := strconv.ParseBool(kv[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing feature gate %s", kv[0])
+		}
+		gates[kv[0]] = enabled
+	}
+	return gates, nil
+}
+
+// setField sets a nested field of a document, creating its parents
+func setField(doc map[string]interface{}, path []string, val interface{}) {
+	for _, p := range path[:len(path)-1] {
+		child, ok := doc[p].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			doc[p] = child
+		}
+		doc = child
+	}
+	doc[path[len(path)-1]] = val
+}
+
+// applyPatch merges a YAML patch into the document with the semantics of a
+// JSON merge patch: objects are merged, lists are replaced and null fields
+// are removed. The result is YAML.
+func applyPatch(doc map[string]interface{}, patch string) ([]byte, error) {
+	docJSON, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding document")
+	}
+	if strings.TrimSpace(patch) != "" {
+		patchJSON, err := yaml.YAMLToJSON([]byte(patch))
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing patch")
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal(patchJSON, &obj); err != nil {
+			return nil, errors.Wrap(err, "patch must be a YAML object")
+		}
+		if docJSON, err = jsonpatch.MergePatch(docJSON, patchJSON); err != nil {
+			return nil, errors.Wrap(err, "merging patch")
+		}
+	}
+	return yaml.JSONToYAML(docJSON)
+}
+
+// indent prefixes the lines of s
+func indent(s, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "")
+}
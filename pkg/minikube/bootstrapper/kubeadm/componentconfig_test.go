@@ -0,0 +1,317 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestApplyPatch(t *testing.T) {
+	doc := func() map[string]interface{} {
+		return map[string]interface{}{
+			"kind":          "KubeletConfiguration",
+			"clusterDNS":    []interface{}{"10.96.0.10"},
+			"authorization": map[string]interface{}{"mode": "Webhook"},
+			"failSwapOn":    false,
+		}
+	}
+	tests := []struct {
+		description string
+		patch       string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "no patch",
+			expected: `authorization:
+  mode: Webhook
+clusterDNS:
+- 10.96.0.10
+failSwapOn: false
+kind: KubeletConfiguration
+`,
+		},
+		{
+			description: "nested merge, list replace and null removal",
+			patch: `authorization:
+  webhook:
+    cacheTTL: 1m
+clusterDNS: [10.0.0.53, 10.0.0.54]
+failSwapOn: null
+`,
+			expected: `authorization:
+  mode: Webhook
+  webhook:
+    cacheTTL: 1m
+clusterDNS:
+- 10.0.0.53
+- 10.0.0.54
+kind: KubeletConfiguration
+`,
+		},
+		{
+			description: "not an object",
+			patch:       "- maxPods: 20\n",
+			shouldErr:   true,
+		},
+		{
+			description: "invalid yaml",
+			patch:       "maxPods: [20\n",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			b, err := applyPatch(doc(), test.patch)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected an error, got:\n%s", b)
+			}
+			if string(b) != test.expected {
+				t.Errorf("Unexpected document. actual:\n%sexpected:\n%s", b, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewKubeletConfiguration(t *testing.T) {
+	opts := map[string]string{
+		"cluster-dns":   "10.96.0.10",
+		"cadvisor-port": "0",
+		"fail-swap-on":  "false",
+		"cgroup-driver": "cgroupfs",
+		"max-pods":      "110",
+	}
+	k8s := config.KubernetesConfig{
+		FeatureGates:       "CustomPodDNS=true",
+		KubeletConfigPatch: "cgroupDriver: systemd\nmaxPods: 20\n",
+	}
+	actual, err := NewKubeletConfiguration(opts, k8s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := `apiVersion: kubelet.config.k8s.io/v1beta1
+authentication:
+  anonymous:
+    enabled: true
+  webhook:
+    enabled: false
+authorization:
+  mode: AlwaysAllow
+cgroupDriver: systemd
+clusterDNS:
+- 10.96.0.10
+failSwapOn: false
+featureGates:
+  CustomPodDNS: true
+kind: KubeletConfiguration
+maxPods: 20
+readOnlyPort: 10255
+`
+	if actual != expected {
+		t.Errorf("Unexpected configuration. actual:\n%sexpected:\n%s", actual, expected)
+	}
+
+	if _, err := NewKubeletConfiguration(map[string]string{"read-only-port": "none"}, config.KubernetesConfig{}); err == nil {
+		t.Error("Expected an error for an invalid option")
+	}
+	if _, err := NewKubeletConfiguration(nil, config.KubernetesConfig{FeatureGates: "CustomPodDNS"}); err == nil {
+		t.Error("Expected an error for an invalid feature gate")
+	}
+}
+
+// TestKubeletConfigurationMatchesFlags checks that the kubelet settings are
+// the same whether the kubelet options are flags or written to the file
+func TestKubeletConfigurationMatchesFlags(t *testing.T) {
+	// The kubelet flag defaults which differ from their v1beta1 fields'
+	flagDefaults := map[string]string{
+		"anonymous-auth":               "true",
+		"authentication-token-webhook": "false",
+		"authorization-mode":           "AlwaysAllow",
+		"read-only-port":               "10255",
+	}
+	fieldDefaults := map[string]string{
+		"anonymous-auth":               "false",
+		"authentication-token-webhook": "true",
+		"authorization-mode":           "Webhook",
+		"read-only-port":               "0",
+	}
+	k8s := config.KubernetesConfig{
+		KubernetesVersion: "v1.10.0",
+		ExtraOptions: util.ExtraOptionSlice{
+			{Component: Kubelet, Key: "anonymous-auth", Value: "false"},
+		},
+	}
+	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		t.Fatalf("Error parsing version: %s", err)
+	}
+	opts, err := kubeletOptions(k8s, version)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	file, err := NewKubeletConfiguration(opts, k8s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(file), &doc); err != nil {
+		t.Fatalf("Error parsing the kubelet configuration: %s", err)
+	}
+
+	for flag, field := range kubeletConfigFields {
+		withFlags, ok := opts[flag]
+		if !ok {
+			withFlags = flagDefaults[flag]
+		}
+		withFile, ok := fieldValue(doc, field.path)
+		if !ok {
+			withFile = fieldDefaults[flag]
+		}
+		if withFlags != withFile {
+			t.Errorf("Expected %s to be %q with the config file as with flags, got %q", flag, withFlags, withFile)
+		}
+	}
+}
+
+// fieldValue returns a field of a parsed document formatted like a flag
+func fieldValue(doc map[string]interface{}, path []string) (string, bool) {
+	var v interface{} = doc
+	for _, p := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if v, ok = m[p]; !ok {
+			return "", false
+		}
+	}
+	if l, ok := v.([]interface{}); ok {
+		var s []string
+		for _, i := range l {
+			s = append(s, fmt.Sprint(i))
+		}
+		return strings.Join(s, ","), true
+	}
+	return fmt.Sprint(v), true
+}
+
+func TestNewKubeProxyConfiguration(t *testing.T) {
+	k8s := config.KubernetesConfig{
+		PodCIDR:              "10.200.0.0/16",
+		KubeProxyConfigPatch: "mode: ipvs\n",
+	}
+	actual, err := NewKubeProxyConfiguration(k8s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := `apiVersion: kubeproxy.config.k8s.io/v1alpha1
+clusterCIDR: 10.200.0.0/16
+kind: KubeProxyConfiguration
+mode: ipvs
+`
+	if actual != expected {
+		t.Errorf("Unexpected configuration. actual:\n%sexpected:\n%s", actual, expected)
+	}
+}
+
+func TestValidateConfigPatches(t *testing.T) {
+	tests := []struct {
+		description string
+		k8s         config.KubernetesConfig
+		shouldErr   bool
+	}{
+		{
+			description: "no patches",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.9.4"},
+		},
+		{
+			description: "patches",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.10.0", KubeletConfigPatch: "maxPods: 20\n", KubeProxyConfigPatch: "mode: ipvs\n"},
+		},
+		{
+			description: "old version",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.9.4", KubeletConfigPatch: "maxPods: 20\n"},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid kube-proxy patch",
+			k8s:         config.KubernetesConfig{KubernetesVersion: "v1.10.0", KubeProxyConfigPatch: "ipvs\n"},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := ValidateConfigPatches(test.k8s)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestNewKubeletConfigFlags(t *testing.T) {
+	tests := []struct {
+		version string
+		present []string
+		absent  []string
+	}{
+		{
+			version: "v1.9.4",
+			present: []string{"--cluster-dns=10.96.0.10", "--cadvisor-port=0", "--feature-gates=CustomPodDNS=true"},
+			absent:  []string{"--config="},
+		},
+		{
+			version: "v1.10.0",
+			present: []string{"--config=" + constants.KubeletConfigFile, "--kubeconfig=", "--cadvisor-port=0"},
+			absent:  []string{"--cluster-dns=", "--authorization-mode=", "--feature-gates="},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			unit, err := NewKubeletConfig(config.KubernetesConfig{
+				KubernetesVersion: test.version,
+				FeatureGates:      "CustomPodDNS=true",
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			for _, flag := range test.present {
+				if !strings.Contains(unit, flag) {
+					t.Errorf("Expected %s in the kubelet unit:\n%s", flag, unit)
+				}
+			}
+			for _, flag := range test.absent {
+				if strings.Contains(unit, flag) {
+					t.Errorf("Unexpected %s in the kubelet unit:\n%s", flag, unit)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+// configurableComponents are the components of all bootstrappers, some of
+// which can't be configured with kubeadm
+var configurableComponents = []string{Kubelet, Apiserver, ControllerManager, Scheduler, "proxy", "etcd"}
+
+// VersionedFlags holds the flags of a component for a range of versions,
+// bounded like a VersionedExtraOption
+type VersionedFlags struct {
+	Component          string
+	Flags              []string
+	LessThanOrEqual    semver.Version
+	GreaterThanOrEqual semver.Version
+}
+
+// commonFlags are the logging flags shared by all components
+var commonFlags = []string{
+	"alsologtostderr", "log-backtrace-at", "log-dir", "log-flush-frequency", "logtostderr",
+	"stderrthreshold", "v", "vmodule",
+}
+
+var componentFlags = []VersionedFlags{
+	{Component: Kubelet, Flags: commonFlags},
+	{Component: Apiserver, Flags: commonFlags},
+	{Component: ControllerManager, Flags: commonFlags},
+	{Component: Scheduler, Flags: commonFlags},
+	{
+		Component: Kubelet,
+		Flags: []string{
+			"address", "allow-privileged", "anonymous-auth", "authentication-token-webhook",
+			"authentication-token-webhook-cache-ttl", "authorization-mode", "authorization-webhook-cache-authorized-ttl",
+			"authorization-webhook-cache-unauthorized-ttl", "azure-container-registry-config", "bootstrap-checkpoint-path",
+			"bootstrap-kubeconfig", "cadvisor-port", "cert-dir", "cgroup-driver", "cgroup-root", "cgroups-per-qos",
+			"chaos-chance", "client-ca-file", "cloud-config", "cloud-provider", "cluster-dns", "cluster-domain",
+			"cni-bin-dir", "cni-conf-dir", "config", "container-runtime", "container-runtime-endpoint", "containerd",
+			"containerized", "contention-profiling", "cpu-cfs-quota", "cpu-manager-policy", "cpu-manager-reconcile-period",
+			"docker-disable-shared-pid", "docker-endpoint", "docker-root", "enable-controller-attach-detach",
+			"enable-custom-metrics", "enable-debugging-handlers", "enable-server", "enforce-node-allocatable", "event-burst",
+			"event-qps", "eviction-hard", "eviction-max-pod-grace-period", "eviction-minimum-reclaim",
+			"eviction-pressure-transition-period", "eviction-soft", "eviction-soft-grace-period", "exit-on-lock-contention",
+			"experimental-allocatable-ignore-eviction", "experimental-bootstrap-kubeconfig",
+			"experimental-check-node-capabilities-before-mount", "experimental-dockershim",
+			"experimental-dockershim-root-directory", "experimental-fail-swap-on", "experimental-kernel-memcg-notification",
+			"experimental-mounter-path", "experimental-qos-reserved", "fail-swap-on", "feature-gates", "file-check-frequency",
+			"google-json-key", "hairpin-mode", "healthz-bind-address", "healthz-port",
+			"host-ipc-sources", "host-network-sources", "host-pid-sources", "hostname-override", "http-check-frequency",
+			"image-gc-high-threshold", "image-gc-low-threshold", "image-pull-progress-deadline", "image-service-endpoint",
+			"iptables-drop-bit", "iptables-masquerade-bit", "keep-terminated-pod-volumes", "kube-api-burst",
+			"kube-api-content-type", "kube-api-qps", "kube-reserved", "kube-reserved-cgroup", "kubeconfig", "kubelet-cgroups",
+			"lock-file", "make-iptables-util-chains", "manifest-url", "manifest-url-header", "master-service-namespace",
+			"max-open-files", "max-pods", "maximum-dead-containers", "maximum-dead-containers-per-container",
+			"minimum-container-ttl-duration", "minimum-image-ttl-duration", "network-plugin", "network-plugin-mtu", "node-ip", "node-labels",
+			"node-status-update-frequency", "non-masquerade-cidr", "oom-score-adj", "pod-cidr", "pod-infra-container-image",
+			"pod-manifest-path", "pods-per-core", "port", "protect-kernel-defaults", "provider-id", "read-only-port",
+			"really-crash-for-testing", "register-node", "register-schedulable", "register-with-taints", "registry-burst",
+			"registry-qps", "resolv-conf", "rkt-api-endpoint", "rkt-path", "rkt-stage1-image", "root-dir",
+			"rotate-certificates", "runonce", "runtime-cgroups", "runtime-request-timeout", "seccomp-profile-root",
+			"serialize-image-pulls", "streaming-connection-idle-timeout", "sync-frequency", "system-cgroups",
+			"system-reserved", "system-reserved-cgroup", "tls-cert-file", "tls-cipher-suites", "tls-min-version",
+			"tls-private-key-file", "version", "volume-plugin-dir", "volume-stats-agg-period",
+			// cadvisor flags
+			"application-metrics-count-limit", "boot-id-file", "container-hints", "docker", "docker-env-metadata-whitelist",
+			"docker-only", "docker-tls", "docker-tls-ca", "docker-tls-cert", "docker-tls-key", "enable-load-reader",
+			"event-storage-age-limit", "event-storage-event-limit", "global-housekeeping-interval", "housekeeping-interval",
+			"log-cadvisor-usage", "machine-id-file", "storage-driver-buffer-duration", "storage-driver-db",
+			"storage-driver-host", "storage-driver-password", "storage-driver-secure", "storage-driver-table",
+			"storage-driver-user",
+		},
+	},
+	{
+		Component:       Kubelet,
+		Flags:           []string{"require-kubeconfig"},
+		LessThanOrEqual: semver.MustParse("1.9.10"),
+	},
+	{
+		Component: Kubelet,
+		Flags: []string{
+			"container-log-max-files", "container-log-max-size", "dynamic-config-dir",
+			"experimental-allowed-unsafe-sysctls", "pod-max-pids",
+		},
+		GreaterThanOrEqual: semver.MustParse("1.10.0-alpha.0"),
+	},
+	{
+		Component: Apiserver,
+		Flags: []string{
+			"address", "admission-control", "admission-control-config-file", "advertise-address", "allow-privileged",
+			"anonymous-auth", "apiserver-count", "audit-log-batch-buffer-size", "audit-log-batch-max-size",
+			"audit-log-batch-max-wait", "audit-log-batch-throttle-burst", "audit-log-batch-throttle-enable",
+			"audit-log-batch-throttle-qps", "audit-log-format", "audit-log-maxage", "audit-log-maxbackup",
+			"audit-log-maxsize", "audit-log-mode", "audit-log-path", "audit-policy-file", "audit-webhook-batch-buffer-size",
+			"audit-webhook-batch-initial-backoff", "audit-webhook-batch-max-size", "audit-webhook-batch-max-wait",
+			"audit-webhook-batch-throttle-burst", "audit-webhook-batch-throttle-enable", "audit-webhook-batch-throttle-qps",
+			"audit-webhook-config-file", "audit-webhook-initial-backoff", "audit-webhook-mode",
+			"authentication-token-webhook-cache-ttl", "authentication-token-webhook-config-file", "authorization-mode",
+			"authorization-policy-file", "authorization-rbac-super-user", "authorization-webhook-cache-authorized-ttl",
+			"authorization-webhook-cache-unauthorized-ttl", "authorization-webhook-config-file", "basic-auth-file",
+			"bind-address", "cert-dir", "client-ca-file", "cloud-config", "cloud-provider", "contention-profiling",
+			"cors-allowed-origins", "default-not-ready-toleration-seconds", "default-unreachable-toleration-seconds",
+			"default-watch-cache-size", "delete-collection-workers", "deserialization-cache-size",
+			"enable-aggregator-routing", "enable-bootstrap-token-auth", "enable-garbage-collector", "enable-logs-handler",
+			"enable-swagger-ui", "endpoint-reconciler-type", "etcd-cafile", "etcd-certfile", "etcd-compaction-interval",
+			"etcd-keyfile", "etcd-prefix", "etcd-quorum-read", "etcd-servers", "etcd-servers-overrides", "event-ttl",
+			"experimental-encryption-provider-config", "external-hostname", "feature-gates", "insecure-bind-address", "insecure-port", "kubelet-certificate-authority",
+			"kubelet-client-certificate", "kubelet-client-key", "kubelet-https", "kubelet-port",
+			"kubelet-preferred-address-types", "kubelet-read-only-port", "kubelet-timeout", "kubernetes-service-node-port",
+			"master-service-namespace", "max-connection-bytes-per-sec", "max-mutating-requests-inflight",
+			"max-requests-inflight", "min-request-timeout", "oidc-ca-file", "oidc-client-id", "oidc-groups-claim",
+			"oidc-groups-prefix", "oidc-issuer-url", "oidc-username-claim", "oidc-username-prefix", "port", "profiling",
+			"proxy-client-cert-file", "proxy-client-key-file", "repair-malformed-updates", "request-timeout",
+			"requestheader-allowed-names", "requestheader-client-ca-file", "requestheader-extra-headers-prefix",
+			"requestheader-group-headers", "requestheader-username-headers", "runtime-config", "secure-port",
+			"service-account-key-file", "service-account-lookup", "service-cluster-ip-range", "service-node-port-range",
+			"ssh-keyfile", "ssh-user", "storage-backend", "storage-media-type", "storage-version", "storage-versions",
+			"target-ram-mb",
+			"tls-ca-file", "tls-cert-file", "tls-cipher-suites", "tls-min-version", "tls-private-key-file",
+			"tls-sni-cert-key", "token-auth-file", "watch-cache", "watch-cache-sizes",
+		},
+	},
+	{
+		Component:       Apiserver,
+		Flags:           []string{"experimental-keystone-ca-file", "experimental-keystone-url"},
+		LessThanOrEqual: semver.MustParse("1.9.10"),
+	},
+	{
+		Component: Apiserver,
+		Flags: []string{
+			"disable-admission-plugins", "enable-admission-plugins", "etcd-count-metric-poll-period",
+			"http2-max-streams-per-connection", "oidc-signing-algs", "service-account-api-audiences",
+			"service-account-issuer", "service-account-signing-key-file",
+		},
+		GreaterThanOrEqual: semver.MustParse("1.10.0-alpha.0"),
+	},
+	{
+		Component: ControllerManager,
+		Flags: []string{
+			"address", "allocate-node-cidrs", "allow-untagged-cloud", "attach-detach-reconcile-sync-period",
+			"azure-container-registry-config",
+			"bind-address", "cert-dir", "cidr-allocator-type", "cloud-config", "cloud-provider", "cluster-cidr",
+			"cluster-name", "cluster-signing-cert-file", "cluster-signing-key-file", "concurrent-deployment-syncs",
+			"concurrent-endpoint-syncs", "concurrent-gc-syncs", "concurrent-namespace-syncs", "concurrent-rc-syncs",
+			"concurrent-replicaset-syncs", "concurrent-resource-quota-syncs", "concurrent-service-syncs",
+			"concurrent-serviceaccount-token-syncs", "configure-cloud-routes", "contention-profiling",
+			"controller-start-interval", "controllers", "deleting-pods-burst", "deleting-pods-qps",
+			"deployment-controller-sync-period",
+			"disable-attach-detach-reconcile-sync", "enable-dynamic-provisioning", "enable-garbage-collector",
+			"enable-hostpath-provisioner", "enable-taint-manager", "experimental-cluster-signing-duration",
+			"external-cloud-volume-plugin", "feature-gates", "flex-volume-plugin-dir",
+			"horizontal-pod-autoscaler-downscale-delay", "horizontal-pod-autoscaler-sync-period",
+			"horizontal-pod-autoscaler-tolerance", "horizontal-pod-autoscaler-upscale-delay",
+			"horizontal-pod-autoscaler-use-rest-clients", "insecure-experimental-approve-all-kubelet-csrs-for-group",
+			"kube-api-burst", "kube-api-content-type", "kube-api-qps", "kubeconfig", "large-cluster-size-threshold",
+			"leader-elect", "leader-elect-lease-duration", "leader-elect-renew-deadline", "leader-elect-resource-lock",
+			"leader-elect-retry-period", "master", "min-resync-period", "namespace-sync-period", "node-cidr-mask-size",
+			"node-eviction-rate", "node-monitor-grace-period", "node-monitor-period", "node-startup-grace-period",
+			"node-sync-period", "pod-eviction-timeout", "port", "profiling", "pv-recycler-increment-timeout-nfs",
+			"pv-recycler-minimum-timeout-hostpath", "pv-recycler-minimum-timeout-nfs",
+			"pv-recycler-pod-template-filepath-hostpath", "pv-recycler-pod-template-filepath-nfs",
+			"pv-recycler-timeout-increment-hostpath", "pvclaimbinder-sync-period", "register-retry-count",
+			"resource-quota-sync-period",
+			"root-ca-file", "route-reconciliation-period", "secondary-node-eviction-rate", "secure-port",
+			"service-account-private-key-file", "service-cluster-ip-range", "terminated-pod-gc-threshold",
+			"tls-cert-file", "tls-cipher-suites", "tls-min-version", "tls-private-key-file", "tls-sni-cert-key",
+			"unhealthy-zone-threshold", "use-service-account-credentials",
+		},
+	},
+	{
+		Component:          ControllerManager,
+		Flags:              []string{"http2-max-streams-per-connection", "tls-ca-file"},
+		GreaterThanOrEqual: semver.MustParse("1.10.0-alpha.0"),
+	},
+	{
+		Component: Scheduler,
+		Flags: []string{
+			"address", "algorithm-provider", "contention-profiling", "failure-domains", "feature-gates",
+			"hard-pod-affinity-symmetric-weight", "kube-api-burst", "kube-api-content-type", "kube-api-qps", "kubeconfig",
+			"leader-elect", "leader-elect-lease-duration", "leader-elect-renew-deadline", "leader-elect-resource-lock",
+			"leader-elect-retry-period", "lock-object-name", "lock-object-namespace", "master", "policy-config-file",
+			"policy-configmap", "policy-configmap-namespace", "port", "profiling", "scheduler-name",
+			"use-legacy-policy-config",
+		},
+	},
+	{
+		Component:          Scheduler,
+		Flags:              []string{"config"},
+		GreaterThanOrEqual: semver.MustParse("1.10.0-alpha.0"),
+	},
+}
+
+// KnownFlags returns the sorted flags of the component in the kubernetes version
+func KnownFlags(component string, version semver.Version) []string {
+	var flags []string
+	for _, f := range componentFlags {
+		if f.Component == component && VersionIsBetween(version, f.GreaterThanOrEqual, f.LessThanOrEqual) {
+			flags = append(flags, f.Flags...)
+		}
+	}
+	sort.Strings(flags)
+	return flags
+}
+
+// ValidateExtraOptions checks the extra options of the cluster before it is
+// provisioned: their components must be configurable with kubeadm, their keys
+// should be flags of the component in the kubernetes version and they must not
+// conflict with the other minikube flags. Options replacing a default set by
+// minikube, and flags which aren't known, are returned as warnings.
+func ValidateExtraOptions(k8s config.KubernetesConfig) ([]string, error) {
+	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing kubernetes version")
+	}
+
+	var warnings []string
+	m := util.MultiError{}
+	seen := map[string]bool{}
+	for _, opt := range k8s.ExtraOptions {
+		if _, ok := componentToKubeadmConfigKey[opt.Component]; !ok {
+			m.Collect(unknownComponentError(opt.Component))
+			continue
+		}
+		flags := KnownFlags(opt.Component, version)
+		if i := sort.SearchStrings(flags, opt.Key); i == len(flags) || flags[i] != opt.Key {
+			warnings = append(warnings, unknownFlagWarning(opt, flags, k8s.KubernetesVersion))
+		}
+		if name := opt.Component + "." + opt.Key; seen[name] {
+			m.Collect(errors.Errorf("%s is set more than once", name))
+		} else {
+			seen[name] = true
+		}
+		if err := conflictingFlag(opt, k8s); err != nil {
+			m.Collect(err)
+			continue
+		}
+
+		defaults, err := DefaultOptionsForComponentAndVersion(opt.Component, version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting the default options for %s", opt.Component)
+		}
+		if val, ok := defaults[opt.Key]; ok && val != opt.Value {
+			warnings = append(warnings, fmt.Sprintf("%s replaces the default %s.%s=%s set by minikube", opt.String(), opt.Component, opt.Key, val))
+		}
+	}
+	return warnings, m.ToError()
+}
+
+func unknownComponentError(component string) error {
+	for _, c := range configurableComponents {
+		if c == component && c == "proxy" {
+			return errors.Errorf("proxy can't be configured with --extra-config with the kubeadm bootstrapper, use --kube-proxy-config-patch")
+		}
+		if c == component {
+			return errors.Errorf("%s can't be configured with the kubeadm bootstrapper", component)
+		}
+	}
+	valid := []string{}
+	for c := range componentToKubeadmConfigKey {
+		valid = append(valid, c)
+	}
+	sort.Strings(valid)
+	msg := fmt.Sprintf("unknown component %q, valid components are: %s", component, strings.Join(valid, ", "))
+	if s, ok := util.Suggest(component, valid); ok {
+		msg += fmt.Sprintf(". Did you mean %q?", s)
+	}
+	return errors.New(msg)
+}
+
+// unknownFlagWarning warns about a flag missing from the known flags, which
+// may still be a flag of the component, e.g. one added in a newer version
+func unknownFlagWarning(opt util.ExtraOption, flags []string, version string) string {
+	msg := fmt.Sprintf("%s is not a known %s flag of kubernetes %s and is passed as is", opt.String(), opt.Component, version)
+	if s, ok := util.Suggest(strings.TrimLeft(opt.Key, "-"), flags); ok {
+		msg += fmt.Sprintf(". Did you mean %q?", s)
+	}
+	return msg
+}
+
+// conflictingFlag returns an error for the options which minikube sets from
+// its own flags
+func conflictingFlag(opt util.ExtraOption, k8s config.KubernetesConfig) error {
+	switch {
+	case opt.Key == "feature-gates" && k8s.FeatureGates != "":
+		return errors.Errorf("%s conflicts with --feature-gates, set the feature gates of all components with --feature-gates", opt.String())
+	case opt.Component == Kubelet && opt.Key == "network-plugin" && opt.Value != "cni" && k8s.CNI != "":
+		return errors.Errorf("%s conflicts with --cni, which needs the cni network plugin", opt.String())
+	case opt.Component == Apiserver && strings.HasPrefix(opt.Key, "audit-") && k8s.AuditPolicy != "":
+		return errors.Errorf("%s conflicts with --audit-policy, which configures the audit log", opt.String())
+	case opt.Component == Apiserver && opt.Key == "admission-control-config-file" && k8s.AdmissionConfig != "":
+		return errors.Errorf("%s conflicts with --admission-config", opt.String())
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/spf13/pflag"
+	scheduler "k8s.io/kubernetes/cmd/kube-scheduler/app"
+	kubelet "k8s.io/kubernetes/cmd/kubelet/app/options"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+// The defaults set by minikube must be flags of their components
+func TestKnownFlagsIncludeDefaults(t *testing.T) {
+	for _, v := range []string{"1.8.0", "1.9.4", "1.10.0"} {
+		version := semver.MustParse(v)
+		for _, opt := range versionSpecificOpts {
+			if !VersionIsBetween(version, opt.GreaterThanOrEqual, opt.LessThanOrEqual) {
+				continue
+			}
+			flags := KnownFlags(opt.Option.Component, version)
+			if i := sort.SearchStrings(flags, opt.Option.Key); i == len(flags) || flags[i] != opt.Option.Key {
+				t.Errorf("Default %s is not a known flag for %s", opt.Option.String(), v)
+			}
+		}
+	}
+}
+
+// The known flags of the vendored kubernetes version must be the flags of the
+// vendored components. Only the kubelet and scheduler are compared: the
+// apiserver options don't build, since the admission plugins need the GCE
+// compute/v0.alpha client which isn't vendored, and the controller-manager
+// options import a codec which panics on init with current Go versions.
+func TestKnownFlagsMatchVendoredFlags(t *testing.T) {
+	version := semver.MustParse("1.10.0")
+	k, err := kubelet.NewKubeletServer()
+	if err != nil {
+		t.Fatalf("Error creating the kubelet options: %s", err)
+	}
+	kubeletFlags := pflag.NewFlagSet(Kubelet, pflag.ContinueOnError)
+	k.AddFlags(kubeletFlags)
+	kubelet.AddGlobalFlags(kubeletFlags)
+	// the scheduler's logging flags are global, so they aren't in its FlagSet
+	schedulerFlags := scheduler.NewSchedulerCommand().Flags()
+	for _, f := range commonFlags {
+		if schedulerFlags.Lookup(f) == nil {
+			schedulerFlags.String(f, "", "")
+		}
+	}
+
+	for component, fs := range map[string]*pflag.FlagSet{Kubelet: kubeletFlags, Scheduler: schedulerFlags} {
+		var vendored []string
+		fs.VisitAll(func(f *pflag.Flag) {
+			vendored = append(vendored, f.Name)
+		})
+		sort.Strings(vendored)
+		if known := KnownFlags(component, version); !reflect.DeepEqual(known, vendored) {
+			t.Errorf("Known %s flags differ from the vendored ones.\nMissing: %q\nUnknown to %s: %q",
+				component, difference(vendored, known), component, difference(known, vendored))
+		}
+	}
+}
+
+// difference returns the sorted flags of a which aren't in b
+func difference(a, b []string) []string {
+	var d []string
+	for _, f := range a {
+		if i := sort.SearchStrings(b, f); i == len(b) || b[i] != f {
+			d = append(d, f)
+		}
+	}
+	return d
+}
+
+func TestValidateExtraOptions(t *testing.T) {
+	tests := []struct {
+		description      string
+		k8s              config.KubernetesConfig
+		expectedWarnings []string
+		expectedErrors   []string
+	}{
+		{
+			description: "valid",
+			k8s: config.KubernetesConfig{
+				KubernetesVersion: "v1.10.0",
+				ExtraOptions: util.ExtraOptionSlice{
+					{Component: Apiserver, Key: "v", Value: "10"},
+					{Component: Kubelet, Key: "max-pods", Value: "100"},
+					{Component: Apiserver, Key: "enable-admission-plugins", Value: "PodSecurityPolicy"},
+				},
+			},
+		},
+		{
+			description: "unknown components",
+			k8s: config.KubernetesConfig{
+				KubernetesVersion: "v1.10.0",
+				ExtraOptions: util.ExtraOptionSlice{
+					{Component: "apiservr", Key: "v", Value: "10"},
+					{Component: "proxy", Key: "v", Value: "10"},
+				},
+			},
+			expectedErrors: []string{
+				`unknown component "apiservr", valid components are: apiserver, controller-manager, kubelet, scheduler. Did you mean "apiserver"?`,
+				"proxy can't be configured with --extra-config with the kubeadm bootstrapper, use --kube-proxy-config-patch",
+			},
+		},
+		{
+			description: "unknown flags",
+			k8s: config.KubernetesConfig{
+				KubernetesVersion: "v1.9.4",
+				ExtraOptions: util.ExtraOptionSlice{
+					{Component: Kubelet, Key: "max-pod", Value: "100"},
+					{Component: Apiserver, Key: "enable-admission-plugins", Value: "PodSecurityPolicy"},
+					{Component: Scheduler, Key: "no-such-flag", Value: "true"},
+				},
+			},
+			expectedWarnings: []string{
+				`kubelet.max-pod=100 is not a known kubelet flag of kubernetes v1.9.4 and is passed as is. Did you mean "max-pods"?`,
+				"apiserver.enable-admission-plugins=PodSecurityPolicy is not a known apiserver flag of kubernetes v1.9.4 and is passed as is",
+				"scheduler.no-such-flag=true is not a known scheduler flag of kubernetes v1.9.4 and is passed as is",
+			},
+		},
+		{
+			description: "unknown flags of a newer version",
+			k8s: config.KubernetesConfig{
+				KubernetesVersion: "v1.11.0",
+				ExtraOptions: util.ExtraOptionSlice{
+					{Component: Apiserver, Key: "new-flag", Value: "true"},
+					{Component: Apiserver, Key: "new-flag", Value: "false"},
+				},
+			},
+			expectedWarnings: []string{
+				"apiserver.new-flag=true is not a known apiserver flag of kubernetes v1.11.0 and is passed as is",
+				"apiserver.new-flag=false is not a known apiserver flag of kubernetes v1.11.0 and is passed as is",
+			},
+			expectedErrors: []string{
+				"apiserver.new-flag is set more than once",
+			},
+		},
+		{
+			description: "conflicts",
+			k8s: config.KubernetesConfig{
+				KubernetesVersion: "v1.10.0",
+				FeatureGates:      "PodPriority=true",
+				CNI:               "flannel",
+				AuditPolicy:       "kind: Policy",
+				AdmissionConfig:   "kind: AdmissionConfiguration",
+				ExtraOptions: util.ExtraOptionSlice{
+					{Component: Apiserver, Key: "feature-gates", Value: "Initializers=true"},
+					{Component: Kubelet, Key: "network-plugin", Value: "kubenet"},
+					{Component: Kubelet, Key: "max-pods", Value: "10"},
+					{Component: Kubelet, Key: "max-pods", Value: "20"},
+					{Component: Apiserver, Key: "audit-log-maxage", Value: "1"},
+					{Component: Apiserver, Key: "admission-control-config-file", Value: "/tmp/admission.yaml"},
+				},
+			},
+			expectedErrors: []string{
+				"apiserver.feature-gates=Initializers=true conflicts with --feature-gates, set the feature gates of all components with --feature-gates",
+				"kubelet.network-plugin=kubenet conflicts with --cni, which needs the cni network plugin",
+				"kubelet.max-pods is set more than once",
+				"apiserver.audit-log-maxage=1 conflicts with --audit-policy, which configures the audit log",
+				"apiserver.admission-control-config-file=/tmp/admission.yaml conflicts with --admission-config",
+			},
+		},
+		{
+			description: "defaults replaced",
+			k8s: config.KubernetesConfig{
+				KubernetesVersion: "v1.10.0",
+				ExtraOptions: util.ExtraOptionSlice{
+					{Component: Kubelet, Key: "cgroup-driver", Value: "systemd"},
+					{Component: Kubelet, Key: "allow-privileged", Value: "true"},
+				},
+			},
+			expectedWarnings: []string{"kubelet.cgroup-driver=systemd replaces the default kubelet.cgroup-driver=cgroupfs set by minikube"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			warnings, err := ValidateExtraOptions(test.k8s)
+			if !reflect.DeepEqual(warnings, test.expectedWarnings) {
+				t.Errorf("Expected warnings %q, got %q", test.expectedWarnings, warnings)
+			}
+			var errs []string
+			if err != nil {
+				errs = strings.Split(err.Error(), "\n")
+			}
+			if !reflect.DeepEqual(errs, test.expectedErrors) {
+				t.Errorf("Expected errors %q, got %q", test.expectedErrors, errs)
+			}
+		})
+	}
+}
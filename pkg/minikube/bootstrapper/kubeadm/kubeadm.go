@@ -28,13 +28,13 @@ import (
 
 	"github.com/blang/semver"
 	"github.com/docker/machine/libmachine"
-	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
 	download "github.com/jimmidyson/go-download"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -67,42 +67,85 @@ func NewKubeadmBootstrapper(api libmachine.API) (*KubeadmBootstrapper, error) {
 	}, nil
 }
 
-//TODO(r2d4): This should most likely check the health of the apiserver
-func (k *KubeadmBootstrapper) GetClusterStatus() (string, error) {
-	statusCmd := `sudo systemctl is-active kubelet &>/dev/null && echo "Running" || echo "Stopped"`
-	status, err := k.c.CombinedOutput(statusCmd)
-	if err != nil {
-		return "", errors.Wrap(err, "getting status")
-	}
-	status = strings.TrimSpace(status)
-	if status == state.Running.String() || status == state.Stopped.String() {
-		return status, nil
-	}
-	return "", fmt.Errorf("Error: Unrecognized output from ClusterStatus: %s", status)
+// GetClusterStatus probes the kubelet and the control plane components it runs
+func (k *KubeadmBootstrapper) GetClusterStatus(k8s config.KubernetesConfig) (bootstrapper.ClusterStatus, error) {
+	return bootstrapper.CheckHealth(k.c, healthChecks(k8s)), nil
 }
 
-// TODO(r2d4): Should this aggregate all the logs from the control plane?
-// Maybe subcommands for each component? minikube logs apiserver?
-func (k *KubeadmBootstrapper) GetClusterLogsTo(follow bool, out io.Writer) error {
-	var flags []string
-	if follow {
-		flags = append(flags, "-f")
+func healthChecks(k8s config.KubernetesConfig) []bootstrapper.HealthCheck {
+	kubelet := bootstrapper.HealthCheck{
+		Name:    bootstrapper.Kubelet,
+		Command: "sudo systemctl is-active kubelet",
+		Expect:  "active",
 	}
-	logsCommand := fmt.Sprintf("sudo journalctl %s -u kubelet", strings.Join(flags, " "))
+	return append([]bootstrapper.HealthCheck{kubelet}, bootstrapper.ControlPlaneHealthChecks(bootstrapper.Kubelet, k8s)...)
+}
+
+// logComponents are the components minikube logs can be scoped to
+var logComponents = []string{
+	bootstrapper.Kubelet,
+	bootstrapper.APIServer,
+	bootstrapper.Etcd,
+	bootstrapper.Scheduler,
+	bootstrapper.ControllerManager,
+	bootstrapper.DNS,
+}
+
+// logContainers are the container names of the components running in pods
+var logContainers = map[string][]string{
+	bootstrapper.APIServer:         {"kube-apiserver"},
+	bootstrapper.Etcd:              {"etcd"},
+	bootstrapper.Scheduler:         {"kube-scheduler"},
+	bootstrapper.ControllerManager: {"kube-controller-manager"},
+	bootstrapper.DNS:               {"kubedns", "coredns"},
+}
 
-	if follow {
-		if err := k.c.CombinedOutputTo(logsCommand, out); err != nil {
-			return errors.Wrap(err, "getting cluster logs")
+// GetClusterLogsTo prints the kubelet journal and the logs of the control plane
+// containers, which are read through the container runtime of the node. The
+// logs of the components which could be found are printed even if others
+// failed, e.g. because the container runtime is broken, and the failures are
+// returned afterwards.
+func (k *KubeadmBootstrapper) GetClusterLogsTo(k8s config.KubernetesConfig, opts bootstrapper.LogOptions, out io.Writer) error {
+	components := opts.Components
+	if len(components) == 0 {
+		components = logComponents
+	}
+	for _, c := range components {
+		if _, ok := logContainers[c]; !ok && c != bootstrapper.Kubelet && c != auditLogComponent {
+			return errors.Errorf("unknown component %q, must be one of: %s", c, strings.Join(append(logComponents, auditLogComponent), ", "))
 		}
-	} else {
+	}
 
-		logs, err := k.c.CombinedOutput(logsCommand)
-		if err != nil {
-			return errors.Wrap(err, "getting cluster logs")
+	m := util.MultiError{}
+	var sources []bootstrapper.LogSource
+	for _, c := range components {
+		if c == bootstrapper.Kubelet {
+			sources = append(sources, bootstrapper.JournalLogSource(c, "kubelet", opts))
+			continue
+		}
+		if c == auditLogComponent {
+			s, err := auditLogSource(k8s, opts)
+			if err != nil {
+				m.Collect(err)
+				continue
+			}
+			sources = append(sources, s)
+			continue
+		}
+		for _, name := range logContainers[c] {
+			s, err := bootstrapper.ContainerLogSources(k.c, k8s.ContainerRuntime, c, name, opts)
+			if err != nil {
+				m.Collect(errors.Wrapf(err, "getting %s logs", c))
+				continue
+			}
+			sources = append(sources, s...)
 		}
-		fmt.Fprint(out, logs)
 	}
-	return nil
+	if len(sources) == 0 && len(m.Errors) == 0 {
+		return errors.Errorf("no containers found for %s", strings.Join(components, ", "))
+	}
+	m.Collect(bootstrapper.WriteLogs(k.c, sources, opts, out))
+	return m.ToError()
 }
 
 func (k *KubeadmBootstrapper) StartCluster(k8s config.KubernetesConfig) error {
@@ -131,6 +174,9 @@ func (k *KubeadmBootstrapper) StartCluster(k8s config.KubernetesConfig) error {
 	if err != nil {
 		return errors.Wrapf(err, "kubeadm init error %s running command: %s", b.String(), out)
 	}
+	if err := k.makeAPIServerVolumesWritable(k8s); err != nil {
+		return err
+	}
 
 	//TODO(r2d4): get rid of global here
 	master = k8s.NodeName
@@ -142,11 +188,14 @@ func (k *KubeadmBootstrapper) StartCluster(k8s config.KubernetesConfig) error {
 		return errors.Wrap(err, "timed out waiting to elevate kube-system RBAC privileges")
 	}
 
+	if k8s.CNI != "" {
+		return waitForNodeReady(k8s)
+	}
 	return nil
 }
 
 //TODO(r2d4): Split out into shared function between localkube and kubeadm
-func addAddons(files *[]assets.CopyableFile) error {
+func addAddons(files *[]assets.CopyableFile, k8sVersion string) error {
 	// add addons to file list
 	// custom addons
 	if err := assets.AddMinikubeDirAssets(files); err != nil {
@@ -159,10 +208,16 @@ func addAddons(files *[]assets.CopyableFile) error {
 		if addonName == "kube-dns" {
 			continue
 		}
+		if !addonBundle.SupportsKubernetesVersion(k8sVersion) {
+			glog.Warningf("Skipping addon %s, it does not support kubernetes version %s", addonName, k8sVersion)
+			continue
+		}
 		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
-			for _, addon := range addonBundle.Assets {
-				*files = append(*files, addon)
+			addonFiles, err := addonBundle.RenderAssets()
+			if err != nil {
+				return errors.Wrapf(err, "rendering addon %s", addonName)
 			}
+			*files = append(*files, addonFiles...)
 		} else if err != nil {
 			return nil
 		}
@@ -186,11 +241,17 @@ func (k *KubeadmBootstrapper) RestartCluster(k8s config.KubernetesConfig) error
 	if err := k.c.Run(b.String()); err != nil {
 		return errors.Wrapf(err, "running cmd: %s", b.String())
 	}
+	if err := k.makeAPIServerVolumesWritable(k8s); err != nil {
+		return err
+	}
 
 	if err := restartKubeProxy(k8s); err != nil {
 		return errors.Wrap(err, "restarting kube-proxy")
 	}
 
+	if k8s.CNI != "" {
+		return waitForNodeReady(k8s)
+	}
 	return nil
 }
 
@@ -225,20 +286,43 @@ func SetContainerRuntime(cfg map[string]string, runtime string) map[string]strin
 	return cfg
 }
 
+// kubeletOptions returns all of the options of the kubelet: minikube's
+// defaults for the version, the extra options, the cluster network and the
+// container runtime.
+func kubeletOptions(k8s config.KubernetesConfig, version semver.Version) (map[string]string, error) {
+	extraOpts, err := ExtraConfigForComponent(Kubelet, k8s.ExtraOptions, version)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating extra configuration for kubelet")
+	}
+
+	if err := setClusterNetworkOptions(extraOpts, k8s); err != nil {
+		return nil, errors.Wrap(err, "setting cluster network options for kubelet")
+	}
+	return SetContainerRuntime(extraOpts, k8s.ContainerRuntime), nil
+}
+
 // NewKubeletConfig generates a new systemd unit containing a configured kubelet
-// based on the options present in the KubernetesConfig.
+// based on the options present in the KubernetesConfig. Kubelets which read a
+// KubeletConfiguration get the options which have a field in it from
+// constants.KubeletConfigFile instead of flags.
 func NewKubeletConfig(k8s config.KubernetesConfig) (string, error) {
 	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
 	if err != nil {
 		return "", errors.Wrap(err, "parsing kubernetes version")
 	}
 
-	extraOpts, err := ExtraConfigForComponent(Kubelet, k8s.ExtraOptions, version)
+	extraOpts, err := kubeletOptions(k8s, version)
 	if err != nil {
-		return "", errors.Wrap(err, "generating extra configuration for kubelet")
+		return "", err
+	}
+	featureGates := k8s.FeatureGates
+	if supportsComponentConfig(version) {
+		for flag := range kubeletConfigFields {
+			delete(extraOpts, flag)
+		}
+		extraOpts["config"] = constants.KubeletConfigFile
+		featureGates = ""
 	}
-
-	extraOpts = SetContainerRuntime(extraOpts, k8s.ContainerRuntime)
 	extraFlags := convertToFlags(extraOpts)
 	b := bytes.Buffer{}
 	opts := struct {
@@ -247,7 +331,7 @@ func NewKubeletConfig(k8s config.KubernetesConfig) (string, error) {
 		ContainerRuntime string
 	}{
 		ExtraOptions:     extraFlags,
-		FeatureGates:     k8s.FeatureGates,
+		FeatureGates:     featureGates,
 		ContainerRuntime: k8s.ContainerRuntime,
 	}
 	if err := kubeletSystemdTemplate.Execute(&b, opts); err != nil {
@@ -257,19 +341,91 @@ func NewKubeletConfig(k8s config.KubernetesConfig) (string, error) {
 	return b.String(), nil
 }
 
+// serviceCIDR returns the service network of the cluster, which isn't set
+// for profiles created before it was configurable.
+func serviceCIDR(k8s config.KubernetesConfig) string {
+	if k8s.ServiceCIDR == "" {
+		return util.DefaultServiceCIDR
+	}
+	return k8s.ServiceCIDR
+}
+
+// setClusterNetworkOptions points the kubelet at the cluster DNS service and
+// domain, and at the CNI plugins when the cluster has a CNI, unless they were
+// set with --extra-config.
+func setClusterNetworkOptions(opts map[string]string, k8s config.KubernetesConfig) error {
+	if _, ok := opts["network-plugin"]; !ok && k8s.CNI != "" {
+		opts["network-plugin"] = "cni"
+	}
+	if _, ok := opts["cluster-dns"]; !ok {
+		dnsIP, err := util.GetDNSIP(serviceCIDR(k8s))
+		if err != nil {
+			return errors.Wrap(err, "getting dns ip")
+		}
+		opts["cluster-dns"] = dnsIP.String()
+	}
+	if _, ok := opts["cluster-domain"]; !ok {
+		domain := k8s.DNSDomain
+		if domain == "" {
+			domain = util.DefaultDNSDomain
+		}
+		opts["cluster-domain"] = domain
+	}
+	return nil
+}
+
 func (k *KubeadmBootstrapper) UpdateCluster(cfg config.KubernetesConfig) error {
 	if cfg.ShouldLoadCachedImages {
 		// Make best effort to load any cached images
 		go machine.LoadImages(k.c, constants.GetKubeadmCachedImages(cfg.KubernetesVersion), constants.ImageCacheDir)
 	}
+	files, err := configFiles(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := k.copyBinaries([]string{"kubelet", "kubeadm"}, cfg.KubernetesVersion); err != nil {
+		return errors.Wrap(err, "downloading binaries")
+	}
+
+	if err := addAddons(&files, cfg.KubernetesVersion); err != nil {
+		return errors.Wrap(err, "adding addons to copyable files")
+	}
+
+	cniFiles, err := cni.Assets(cfg)
+	if err != nil {
+		return errors.Wrap(err, "adding cni to copyable files")
+	}
+	files = append(files, cniFiles...)
+
+	for _, f := range files {
+		if err := k.c.Copy(f); err != nil {
+			return errors.Wrapf(err, "transferring kubeadm file: %+v", f)
+		}
+	}
+
+	err = k.c.Run(`
+sudo systemctl daemon-reload &&
+sudo systemctl enable kubelet &&
+sudo systemctl start kubelet
+`)
+	if err != nil {
+		return errors.Wrap(err, "starting kubelet")
+	}
+
+	return nil
+}
+
+// configFiles returns the kubelet service and the kubelet and kubeadm configs
+func configFiles(cfg config.KubernetesConfig) ([]assets.CopyableFile, error) {
 	kubeadmCfg, err := generateConfig(cfg)
 	if err != nil {
-		return errors.Wrap(err, "generating kubeadm cfg")
+		return nil, errors.Wrap(err, "generating kubeadm cfg")
 	}
 
 	kubeletCfg, err := NewKubeletConfig(cfg)
 	if err != nil {
-		return errors.Wrap(err, "generating kubelet config")
+		return nil, errors.Wrap(err, "generating kubelet config")
 	}
 
 	files := []assets.CopyableFile{
@@ -278,11 +434,31 @@ func (k *KubeadmBootstrapper) UpdateCluster(cfg config.KubernetesConfig) error {
 		assets.NewMemoryAssetTarget([]byte(kubeadmCfg), constants.KubeadmConfigFile, "0640"),
 	}
 
+	version, err := ParseKubernetesVersion(cfg.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing kubernetes version")
+	}
+	if supportsComponentConfig(version) {
+		opts, err := kubeletOptions(cfg, version)
+		if err != nil {
+			return nil, err
+		}
+		kubeletConfiguration, err := NewKubeletConfiguration(opts, cfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "generating kubelet configuration")
+		}
+		files = append(files, assets.NewMemoryAssetTarget([]byte(kubeletConfiguration), constants.KubeletConfigFile, "0640"))
+	}
+	return append(files, apiServerFileAssets(cfg)...), nil
+}
+
+// copyBinaries downloads the binaries of the kubernetes version and copies them to the VM
+func (k *KubeadmBootstrapper) copyBinaries(binaries []string, version string) error {
 	var g errgroup.Group
-	for _, bin := range []string{"kubelet", "kubeadm"} {
+	for _, bin := range binaries {
 		bin := bin
 		g.Go(func() error {
-			path, err := maybeDownloadAndCache(bin, cfg.KubernetesVersion)
+			path, err := maybeDownloadAndCache(bin, version)
 			if err != nil {
 				return errors.Wrapf(err, "downloading %s", bin)
 			}
@@ -296,30 +472,7 @@ func (k *KubeadmBootstrapper) UpdateCluster(cfg config.KubernetesConfig) error {
 			return nil
 		})
 	}
-	if err := g.Wait(); err != nil {
-		return errors.Wrap(err, "downloading binaries")
-	}
-
-	if err := addAddons(&files); err != nil {
-		return errors.Wrap(err, "adding addons to copyable files")
-	}
-
-	for _, f := range files {
-		if err := k.c.Copy(f); err != nil {
-			return errors.Wrapf(err, "transferring kubeadm file: %+v", f)
-		}
-	}
-
-	err = k.c.Run(`
-sudo systemctl daemon-reload &&
-sudo systemctl enable kubelet &&
-sudo systemctl start kubelet
-`)
-	if err != nil {
-		return errors.Wrap(err, "starting kubelet")
-	}
-
-	return nil
+	return g.Wait()
 }
 
 func generateConfig(k8s config.KubernetesConfig) (string, error) {
@@ -334,23 +487,44 @@ func generateConfig(k8s config.KubernetesConfig) (string, error) {
 		return "", errors.Wrap(err, "generating extra component config for kubeadm")
 	}
 
+	apiServerFlags, apiServerVolumes := apiServerFiles(k8s)
+	extraComponentConfig = addAPIServerArgs(extraComponentConfig, apiServerFlags)
+
+	// kube-proxy is configured by kubeadm, only patches need a config
+	var kubeProxyConfig string
+	if supportsComponentConfig(version) && k8s.KubeProxyConfigPatch != "" {
+		cfg, err := NewKubeProxyConfiguration(k8s)
+		if err != nil {
+			return "", errors.Wrap(err, "generating kube-proxy configuration")
+		}
+		kubeProxyConfig = indent(cfg, "    ")
+	}
+
 	opts := struct {
 		CertDir           string
 		ServiceCIDR       string
+		PodSubnet         string
+		DNSDomain         string
 		AdvertiseAddress  string
 		APIServerPort     int
 		KubernetesVersion string
 		EtcdDataDir       string
 		NodeName          string
+		KubeProxyConfig   string
+		APIServerVolumes  []hostPathMount
 		ExtraArgs         []ComponentExtraArgs
 	}{
 		CertDir:           util.DefaultCertPath,
-		ServiceCIDR:       util.DefaultServiceCIDR,
+		ServiceCIDR:       serviceCIDR(k8s),
+		PodSubnet:         cni.PodCIDR(k8s),
+		DNSDomain:         k8s.DNSDomain,
 		AdvertiseAddress:  k8s.NodeIP,
 		APIServerPort:     util.APIServerPort,
 		KubernetesVersion: k8s.KubernetesVersion,
 		EtcdDataDir:       "/data", //TODO(r2d4): change to something else persisted
 		NodeName:          k8s.NodeName,
+		KubeProxyConfig:   kubeProxyConfig,
+		APIServerVolumes:  apiServerVolumes,
 		ExtraArgs:         extraComponentConfig,
 	}
 
@@ -17,8 +17,11 @@ limitations under the License.
 package kubeadm
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/util"
 )
@@ -199,6 +202,128 @@ controllerManagerExtraArgs:
   feature-gates: "HugePages=true,OtherFeature=false"
 schedulerExtraArgs:
   feature-gates: "HugePages=true,OtherFeature=false"
+`,
+		},
+		{
+			description: "custom cluster networks",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				KubernetesVersion: "v1.10.0",
+				NodeName:          "minikube",
+				ServiceCIDR:       "172.30.0.0/16",
+				PodCIDR:           "10.244.0.0/16",
+				DNSDomain:         "corp.example",
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.100
+  bindPort: 8443
+kubernetesVersion: v1.10.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 172.30.0.0/16
+  podSubnet: 10.244.0.0/16
+  dnsDomain: corp.example
+etcd:
+  dataDir: /data
+nodeName: minikube
+apiServerExtraArgs:
+  admission-control: "Initializers,NamespaceLifecycle,LimitRanger,ServiceAccount,DefaultStorageClass,DefaultTolerationSeconds,NodeRestriction,MutatingAdmissionWebhook,ValidatingAdmissionWebhook,ResourceQuota"
+`,
+		},
+		{
+			description: "cni default pod network",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				KubernetesVersion: "v1.10.0",
+				NodeName:          "minikube",
+				CNI:               "flannel",
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.100
+  bindPort: 8443
+kubernetesVersion: v1.10.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 10.96.0.0/12
+  podSubnet: 10.244.0.0/16
+etcd:
+  dataDir: /data
+nodeName: minikube
+apiServerExtraArgs:
+  admission-control: "Initializers,NamespaceLifecycle,LimitRanger,ServiceAccount,DefaultStorageClass,DefaultTolerationSeconds,NodeRestriction,MutatingAdmissionWebhook,ValidatingAdmissionWebhook,ResourceQuota"
+`,
+		},
+		{
+			description: "kube-proxy config patch",
+			cfg: config.KubernetesConfig{
+				NodeIP:               "192.168.1.100",
+				KubernetesVersion:    "v1.10.0",
+				NodeName:             "minikube",
+				KubeProxyConfigPatch: "mode: ipvs\n",
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.100
+  bindPort: 8443
+kubernetesVersion: v1.10.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 10.96.0.0/12
+etcd:
+  dataDir: /data
+nodeName: minikube
+kubeProxy:
+  config:
+    apiVersion: kubeproxy.config.k8s.io/v1alpha1
+    kind: KubeProxyConfiguration
+    mode: ipvs
+apiServerExtraArgs:
+  admission-control: "Initializers,NamespaceLifecycle,LimitRanger,ServiceAccount,DefaultStorageClass,DefaultTolerationSeconds,NodeRestriction,MutatingAdmissionWebhook,ValidatingAdmissionWebhook,ResourceQuota"
+`,
+		},
+		{
+			description: "audit policy and admission config",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				KubernetesVersion: "v1.10.0",
+				NodeName:          "minikube",
+				AuditPolicy:       "kind: Policy",
+				AdmissionConfig:   "kind: AdmissionConfiguration",
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.100
+  bindPort: 8443
+kubernetesVersion: v1.10.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 10.96.0.0/12
+etcd:
+  dataDir: /data
+nodeName: minikube
+apiServerExtraVolumes:
+- name: audit-policy
+  hostPath: /etc/kubernetes/audit
+  mountPath: /etc/kubernetes/audit
+- name: audit-log
+  hostPath: /var/log/kubernetes/audit
+  mountPath: /var/log/kubernetes/audit
+- name: admission-config
+  hostPath: /etc/kubernetes/admission
+  mountPath: /etc/kubernetes/admission
+apiServerExtraArgs:
+  admission-control: "Initializers,NamespaceLifecycle,LimitRanger,ServiceAccount,DefaultStorageClass,DefaultTolerationSeconds,NodeRestriction,MutatingAdmissionWebhook,ValidatingAdmissionWebhook,ResourceQuota"
+  admission-control-config-file: "/etc/kubernetes/admission/config.yaml"
+  audit-log-maxbackup: "5"
+  audit-log-maxsize: "100"
+  audit-log-path: "/var/log/kubernetes/audit/audit.log"
+  audit-policy-file: "/etc/kubernetes/audit/policy.yaml"
 `,
 		},
 		{
@@ -238,3 +363,235 @@ schedulerExtraArgs:
 		})
 	}
 }
+
+func TestKubeletClusterNetworkOptions(t *testing.T) {
+	tests := []struct {
+		description    string
+		cfg            config.KubernetesConfig
+		expectedDNS    string
+		expectedDomain string
+		expectedPlugin string
+	}{
+		{
+			description:    "defaults",
+			cfg:            config.KubernetesConfig{},
+			expectedDNS:    "10.96.0.10",
+			expectedDomain: "cluster.local",
+		},
+		{
+			description:    "from the profile",
+			cfg:            config.KubernetesConfig{ServiceCIDR: "172.30.0.0/16", DNSDomain: "corp.example"},
+			expectedDNS:    "172.30.0.10",
+			expectedDomain: "corp.example",
+		},
+		{
+			description:    "cni",
+			cfg:            config.KubernetesConfig{CNI: "calico"},
+			expectedDNS:    "10.96.0.10",
+			expectedDomain: "cluster.local",
+			expectedPlugin: "cni",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			opts := map[string]string{}
+			if err := setClusterNetworkOptions(opts, test.cfg); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if opts["cluster-dns"] != test.expectedDNS {
+				t.Errorf("Expected cluster-dns %s, got %s", test.expectedDNS, opts["cluster-dns"])
+			}
+			if opts["cluster-domain"] != test.expectedDomain {
+				t.Errorf("Expected cluster-domain %s, got %s", test.expectedDomain, opts["cluster-domain"])
+			}
+			if opts["network-plugin"] != test.expectedPlugin {
+				t.Errorf("Expected network-plugin %q, got %q", test.expectedPlugin, opts["network-plugin"])
+			}
+		})
+	}
+
+	// Options set with --extra-config are kept
+	opts := map[string]string{"cluster-dns": "10.0.0.53"}
+	if err := setClusterNetworkOptions(opts, config.KubernetesConfig{}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if opts["cluster-dns"] != "10.0.0.53" {
+		t.Errorf("Expected cluster-dns to be kept, got %s", opts["cluster-dns"])
+	}
+}
+
+func TestGetClusterStatus(t *testing.T) {
+	k8s := config.KubernetesConfig{DNSDomain: "example.local", ServiceCIDR: "10.0.0.0/24"}
+	checks := healthChecks(k8s)
+	healthy := map[string]string{}
+	for _, c := range checks {
+		healthy[c.Command] = c.Expect
+	}
+	withDNSDown := map[string]string{}
+	for k, v := range healthy {
+		if k != "timeout 5 nslookup kubernetes.default.svc.example.local 10.0.0.10" {
+			withDNSDown[k] = v
+		}
+	}
+	withSchedulerDown := map[string]string{}
+	for k, v := range healthy {
+		withSchedulerDown[k] = v
+	}
+	withSchedulerDown["curl -sSk --max-time 5 http://127.0.0.1:10251/healthz"] = "[-]leaderelection failed"
+
+	tests := []struct {
+		description string
+		outputs     map[string]string
+		expected    string
+		components  map[string]string
+	}{
+		{
+			description: "healthy",
+			outputs:     healthy,
+			expected:    "Running",
+			components:  map[string]string{bootstrapper.Kubelet: "Running", bootstrapper.DNS: "Running"},
+		},
+		{
+			description: "kubelet stopped",
+			outputs:     map[string]string{"sudo systemctl is-active kubelet": "inactive"},
+			expected:    "Stopped",
+			components:  map[string]string{bootstrapper.Kubelet: "Stopped", bootstrapper.Scheduler: "Stopped"},
+		},
+		{
+			description: "dns is optional",
+			outputs:     withDNSDown,
+			expected:    "Running",
+			components:  map[string]string{bootstrapper.DNS: "Error"},
+		},
+		{
+			description: "unhealthy scheduler",
+			outputs:     withSchedulerDown,
+			expected:    "Error",
+			components:  map[string]string{bootstrapper.Scheduler: "Error", bootstrapper.ControllerManager: "Running"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			f := bootstrapper.NewFakeCommandRunner()
+			f.SetCommandToOutput(test.outputs)
+			k := &KubeadmBootstrapper{c: f}
+			status, err := k.GetClusterStatus(k8s)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if status.State != test.expected {
+				t.Errorf("Expected cluster state %s, got %s: %+v", test.expected, status.State, status.Components)
+			}
+			for name, expected := range test.components {
+				c, ok := status.Component(name)
+				if !ok || c.State != expected {
+					t.Errorf("Expected %s to be %s, got %+v", name, expected, c)
+				}
+			}
+		})
+	}
+}
+
+func TestGetClusterLogsTo(t *testing.T) {
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo journalctl --no-pager -q -o short-iso -n 5 -u kubelet": "2018-05-01T10:00:01+0000 minikube kubelet[1]: started",
+		"docker ps -a -q --filter name=k8s_kube-apiserver_":          "abc",
+		"docker logs --timestamps --tail=5 abc":                      "2018-05-01T10:00:00Z I0501 serving",
+		"docker ps -a -q --filter name=k8s_etcd_":                    "",
+		"docker ps -a -q --filter name=k8s_kube-scheduler_":          "",
+		"docker ps -a -q --filter name=k8s_kube-controller-manager_": "",
+		"docker ps -a -q --filter name=k8s_kubedns_":                 "",
+		"docker ps -a -q --filter name=k8s_coredns_":                 "",
+		"sudo tail -n 5 /var/log/kubernetes/audit/audit.log":         `{"kind":"Event","verb":"get"}`,
+	})
+	k := &KubeadmBootstrapper{c: f}
+
+	tests := []struct {
+		description string
+		components  []string
+		k8s         config.KubernetesConfig
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "kubelet",
+			components:  []string{"kubelet"},
+			expected:    "2018-05-01T10:00:01+0000 minikube kubelet[1]: started",
+		},
+		{
+			description: "all components",
+			expected: "[apiserver] 2018-05-01T10:00:00Z I0501 serving\n" +
+				"[kubelet] 2018-05-01T10:00:01+0000 minikube kubelet[1]: started\n",
+		},
+		{
+			description: "no containers",
+			components:  []string{"etcd"},
+			shouldErr:   true,
+		},
+		{
+			description: "unknown component",
+			components:  []string{"kube-proxy"},
+			shouldErr:   true,
+		},
+		{
+			description: "audit",
+			components:  []string{"audit"},
+			k8s:         config.KubernetesConfig{AuditPolicy: "kind: Policy"},
+			expected:    `{"kind":"Event","verb":"get"}`,
+		},
+		{
+			description: "audit disabled",
+			components:  []string{"audit"},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var b bytes.Buffer
+			opts := bootstrapper.LogOptions{Components: test.components, Tail: 5}
+			err := k.GetClusterLogsTo(test.k8s, opts, &b)
+			if err != nil {
+				if !test.shouldErr {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if test.shouldErr {
+				t.Fatal("Expected an error")
+			}
+			if b.String() != test.expected {
+				t.Errorf("Expected logs:\n%s\ngot:\n%s", test.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestGetClusterLogsToPartial(t *testing.T) {
+	// The container runtime fails to list etcd and dns containers
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo journalctl --no-pager -q -o short-iso -u kubelet":      "2018-05-01T10:00:01+0000 minikube kubelet[1]: started",
+		"docker ps -a -q --filter name=k8s_kube-apiserver_":          "abc",
+		"docker logs --timestamps abc":                               "2018-05-01T10:00:00Z I0501 serving",
+		"docker ps -a -q --filter name=k8s_kube-scheduler_":          "",
+		"docker ps -a -q --filter name=k8s_kube-controller-manager_": "",
+	})
+	k := &KubeadmBootstrapper{c: f}
+
+	var b bytes.Buffer
+	err := k.GetClusterLogsTo(config.KubernetesConfig{}, bootstrapper.LogOptions{}, &b)
+	if err == nil {
+		t.Fatal("Expected an error for the components which couldn't be listed")
+	}
+	for _, c := range []string{"etcd", "dns"} {
+		if !strings.Contains(err.Error(), "getting "+c+" logs") {
+			t.Errorf("Expected the error to report %s, got: %s", c, err)
+		}
+	}
+	expected := "[apiserver] 2018-05-01T10:00:00Z I0501 serving\n" +
+		"[kubelet] 2018-05-01T10:00:01+0000 minikube kubelet[1]: started\n"
+	if b.String() != expected {
+		t.Errorf("Expected logs:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
@@ -32,11 +32,19 @@ api:
 kubernetesVersion: {{.KubernetesVersion}}
 certificatesDir: {{.CertDir}}
 networking:
-  serviceSubnet: {{.ServiceCIDR}}
+  serviceSubnet: {{.ServiceCIDR}}{{if .PodSubnet}}
+  podSubnet: {{.PodSubnet}}{{end}}{{if .DNSDomain}}
+  dnsDomain: {{.DNSDomain}}{{end}}
 etcd:
   dataDir: {{.EtcdDataDir}}
 nodeName: {{.NodeName}}
-{{range .ExtraArgs}}{{.Component}}:{{range $i, $val := printMapInOrder .Options ": " }}
+{{if .KubeProxyConfig}}kubeProxy:
+  config:
+{{.KubeProxyConfig}}{{end}}{{if .APIServerVolumes}}apiServerExtraVolumes:{{range .APIServerVolumes}}
+- name: {{.Name}}
+  hostPath: {{.HostPath}}
+  mountPath: {{.MountPath}}{{end}}
+{{end}}{{range .ExtraArgs}}{{.Component}}:{{range $i, $val := printMapInOrder .Options ": " }}
   {{$val}}{{end}}
 {{end}}`))
 
@@ -73,6 +81,9 @@ sudo /usr/bin/kubeadm alpha phase controlplane all --config {{.KubeadmConfigFile
 sudo /usr/bin/kubeadm alpha phase etcd local --config {{.KubeadmConfigFile}}
 `))
 
+var kubeadmUpgradeTemplate = template.Must(template.New("kubeadmUpgradeTemplate").Parse(
+	"sudo /usr/bin/kubeadm upgrade apply {{.KubernetesVersion}} --config {{.KubeadmConfigFile}} --yes {{if .SkipPreflightChecks}}--skip-preflight-checks{{else}}{{range .Preflights}}--ignore-preflight-errors={{.}} {{end}}{{end}}"))
+
 var kubeadmInitTemplate = template.Must(template.New("kubeadmInitTemplate").Parse(
 	"sudo /usr/bin/kubeadm init --config {{.KubeadmConfigFile}} {{if .SkipPreflightChecks}}--skip-preflight-checks{{else}}{{range .Preflights}}--ignore-preflight-errors={{.}} {{end}}{{end}}"))
 
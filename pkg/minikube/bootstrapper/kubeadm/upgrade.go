@@ -0,0 +1,196 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// minimumUpgradeVersion is the first version with kubeadm upgrade
+var minimumUpgradeVersion = semver.MustParse("1.8.0")
+
+// upgradeBinaries are replaced by an upgrade, and restored if it fails
+var upgradeBinaries = []string{"kubeadm", "kubelet"}
+
+// ValidateUpgrade checks that kubeadm supports upgrading a cluster between
+// the kubernetes versions: only upgrades to the next minor version are.
+func ValidateUpgrade(from, to string) error {
+	if !strings.HasPrefix(from, "v") || !strings.HasPrefix(to, "v") {
+		return errors.Errorf("kubernetes versions must start with a v, got %q and %q", from, to)
+	}
+	fromVersion, err := ParseKubernetesVersion(from)
+	if err != nil {
+		return errors.Wrap(err, "parsing the cluster version")
+	}
+	toVersion, err := ParseKubernetesVersion(to)
+	if err != nil {
+		return errors.Wrap(err, "parsing the upgrade version")
+	}
+	if fromVersion.LT(minimumUpgradeVersion) {
+		return errors.Errorf("clusters older than v%s can't be upgraded, recreate the cluster with minikube delete", minimumUpgradeVersion)
+	}
+	if !toVersion.GT(fromVersion) {
+		return errors.Errorf("%s is not newer than the cluster version %s, downgrades are not supported", to, from)
+	}
+	if toVersion.Major != fromVersion.Major || toVersion.Minor > fromVersion.Minor+1 {
+		return errors.Errorf("kubeadm can only upgrade one minor version at a time, upgrade to v%d.%d first", fromVersion.Major, fromVersion.Minor+1)
+	}
+	return nil
+}
+
+// UpgradeCluster upgrades the control plane with kubeadm upgrade, then the
+// kubelet. The binaries and configs of the current version are restored if
+// the upgrade fails, kubeadm restores the control plane manifests itself.
+func (k *KubeadmBootstrapper) UpgradeCluster(current, target config.KubernetesConfig) error {
+	if err := ValidateUpgrade(current.KubernetesVersion, target.KubernetesVersion); err != nil {
+		return err
+	}
+
+	glog.Infoln("Pulling the images of", target.KubernetesVersion)
+	images := constants.GetKubeadmCachedImages(target.KubernetesVersion)
+	if err := machine.CacheImages(images, constants.ImageCacheDir); err != nil {
+		return errors.Wrap(err, "caching images")
+	}
+	if err := machine.LoadImages(k.c, images, constants.ImageCacheDir); err != nil {
+		return errors.Wrap(err, "loading images")
+	}
+
+	if err := k.c.Run(binariesCommand("sudo cp -p /usr/bin/%[1]s /usr/bin/%[1]s.bak")); err != nil {
+		return errors.Wrap(err, "backing up binaries")
+	}
+	if err := k.upgrade(target); err != nil {
+		glog.Errorf("Upgrade failed, rolling back to %s: %s", current.KubernetesVersion, err)
+		if rerr := k.rollback(current); rerr != nil {
+			glog.Errorf("Error rolling back the upgrade: %s", rerr)
+			return errors.Wrapf(err, "upgrade failed and so did the rollback (%s)", rerr)
+		}
+		return errors.Wrapf(err, "upgrade failed, rolled back to %s", current.KubernetesVersion)
+	}
+	if err := k.c.Run(binariesCommand("sudo rm -f /usr/bin/%[1]s.bak")); err != nil {
+		glog.Warningf("Error removing the binary backups: %s", err)
+	}
+	return nil
+}
+
+func (k *KubeadmBootstrapper) upgrade(target config.KubernetesConfig) error {
+	glog.Infoln("Upgrading the control plane")
+	if err := k.copyBinaries([]string{"kubeadm"}, target.KubernetesVersion); err != nil {
+		return errors.Wrap(err, "copying kubeadm")
+	}
+	if err := k.copyConfigs(target); err != nil {
+		return err
+	}
+	cmd, err := upgradeCommand(target.KubernetesVersion)
+	if err != nil {
+		return err
+	}
+	if out, err := k.c.CombinedOutput(cmd); err != nil {
+		return errors.Wrapf(err, "kubeadm upgrade: %s", out)
+	}
+	if err := k.makeAPIServerVolumesWritable(target); err != nil {
+		return err
+	}
+
+	glog.Infoln("Upgrading the kubelet")
+	if err := k.c.Run("sudo systemctl stop kubelet"); err != nil {
+		return errors.Wrap(err, "stopping kubelet")
+	}
+	if err := k.copyBinaries([]string{"kubelet"}, target.KubernetesVersion); err != nil {
+		return errors.Wrap(err, "copying kubelet")
+	}
+	return k.restartKubelet()
+}
+
+// upgradeCommand returns the kubeadm upgrade command of the target version,
+// whose kubeadm has replaced the current one. Like kubeadm init, kubeadm
+// before v1.9 only has --skip-preflight-checks.
+func upgradeCommand(kubernetesVersion string) (string, error) {
+	version, err := ParseKubernetesVersion(kubernetesVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing kubernetes version")
+	}
+	b := bytes.Buffer{}
+	opts := struct {
+		KubernetesVersion   string
+		KubeadmConfigFile   string
+		SkipPreflightChecks bool
+		Preflights          []string
+	}{
+		KubernetesVersion: kubernetesVersion,
+		KubeadmConfigFile: constants.KubeadmConfigFile,
+		SkipPreflightChecks: !VersionIsBetween(version,
+			semver.MustParse("1.9.0-alpha.0"),
+			semver.Version{}),
+		Preflights: constants.Preflights,
+	}
+	if err := kubeadmUpgradeTemplate.Execute(&b, opts); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// rollback restores the binaries and configs of the current version
+func (k *KubeadmBootstrapper) rollback(current config.KubernetesConfig) error {
+	if err := k.c.Run("sudo systemctl stop kubelet"); err != nil {
+		return errors.Wrap(err, "stopping kubelet")
+	}
+	if err := k.c.Run(binariesCommand("sudo mv -f /usr/bin/%[1]s.bak /usr/bin/%[1]s")); err != nil {
+		return errors.Wrap(err, "restoring binaries")
+	}
+	if err := k.copyConfigs(current); err != nil {
+		return err
+	}
+	return k.restartKubelet()
+}
+
+func (k *KubeadmBootstrapper) copyConfigs(cfg config.KubernetesConfig) error {
+	files, err := configFiles(cfg)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := k.c.Copy(f); err != nil {
+			return errors.Wrapf(err, "transferring kubeadm file: %+v", f)
+		}
+	}
+	return nil
+}
+
+func (k *KubeadmBootstrapper) restartKubelet() error {
+	if err := k.c.Run("sudo systemctl daemon-reload && sudo systemctl restart kubelet"); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
+	}
+	return nil
+}
+
+// binariesCommand runs a command on each of the upgraded binaries
+func binariesCommand(format string) string {
+	var cmds []string
+	for _, bin := range upgradeBinaries {
+		cmds = append(cmds, fmt.Sprintf(format, bin))
+	}
+	return strings.Join(cmds, " && ")
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateUpgrade(t *testing.T) {
+	tests := []struct {
+		from      string
+		to        string
+		shouldErr bool
+	}{
+		{from: "v1.9.4", to: "v1.10.0"},
+		{from: "v1.10.0", to: "v1.10.1"},
+		{from: "v1.8.0", to: "v1.9.0-beta.1"},
+		{from: "v1.10.0", to: "v1.10.0", shouldErr: true},
+		{from: "v1.10.0", to: "v1.9.4", shouldErr: true},
+		{from: "v1.8.0", to: "v1.10.0", shouldErr: true},
+		{from: "v1.7.5", to: "v1.8.0", shouldErr: true},
+		{from: "v1.10.0", to: "v2.0.0", shouldErr: true},
+		{from: "v1.10.0", to: "1.10.1", shouldErr: true},
+		{from: "v1.10.0", to: "", shouldErr: true},
+		{from: "v1.10.0", to: "vlatest", shouldErr: true},
+	}
+	for _, test := range tests {
+		err := ValidateUpgrade(test.from, test.to)
+		if err != nil && !test.shouldErr {
+			t.Errorf("Unexpected error upgrading %s to %s: %s", test.from, test.to, err)
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("Expected an error upgrading %s to %s", test.from, test.to)
+		}
+	}
+}
+
+func TestBinariesCommand(t *testing.T) {
+	expected := "sudo rm -f /usr/bin/kubeadm.bak && sudo rm -f /usr/bin/kubelet.bak"
+	if actual := binariesCommand("sudo rm -f /usr/bin/%[1]s.bak"); actual != expected {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+}
+
+func TestUpgradeCommand(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{version: "v1.8.3", expected: "--skip-preflight-checks"},
+		{version: "v1.9.0-beta.1", expected: "--ignore-preflight-errors=DirAvailable--etc-kubernetes-manifests "},
+		{version: "v1.10.0", expected: "--ignore-preflight-errors=DirAvailable--etc-kubernetes-manifests "},
+	}
+	for _, test := range tests {
+		cmd, err := upgradeCommand(test.version)
+		if err != nil {
+			t.Fatalf("Error getting the upgrade command of %s: %s", test.version, err)
+		}
+		if !strings.HasPrefix(cmd, "sudo /usr/bin/kubeadm upgrade apply "+test.version+" ") {
+			t.Errorf("Unexpected upgrade command of %s: %s", test.version, cmd)
+		}
+		if !strings.Contains(cmd, test.expected) {
+			t.Errorf("Expected the upgrade command of %s to contain %q, got %s", test.version, test.expected, cmd)
+		}
+		if strings.Contains(test.expected, "skip") == strings.Contains(cmd, "--ignore-preflight-errors") {
+			t.Errorf("Expected only one of the preflight flags in %s", cmd)
+		}
+	}
+	if _, err := upgradeCommand("latest"); err == nil {
+		t.Error("Expected an error for an invalid version")
+	}
+}
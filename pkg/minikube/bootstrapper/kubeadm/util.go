@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"html/template"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -40,6 +41,8 @@ import (
 const (
 	masterTaint = "node-role.kubernetes.io/master"
 	rbacName    = "minikube-rbac"
+	// nodeReadyTimeout allows for pulling the images of the CNI
+	nodeReadyTimeout = 10 * time.Minute
 )
 
 var master = ""
@@ -199,3 +202,16 @@ func restartKubeProxy(k8s config.KubernetesConfig) error {
 
 	return nil
 }
+
+// waitForNodeReady waits for the node to be ready, which a node with a CNI
+// only is once the CNI is running.
+func waitForNodeReady(k8s config.KubernetesConfig) error {
+	client, err := util.GetClient()
+	if err != nil {
+		return errors.Wrap(err, "getting k8s client")
+	}
+	if err := util.WaitForNodeReady(client, k8s.NodeName, nodeReadyTimeout); err != nil {
+		return errors.Wrapf(err, "waiting for node %s to be ready with cni %s", k8s.NodeName, k8s.CNI)
+	}
+	return nil
+}
@@ -23,8 +23,11 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
 )
 
 // Kill any running instances.
@@ -160,6 +163,10 @@ func GenLocalkubeStartCmd(kubernetesConfig config.KubernetesConfig) (string, err
 		flagVals = append(flagVals, "--dns-domain="+kubernetesConfig.DNSDomain)
 	}
 
+	if kubernetesConfig.ServiceCIDR != "" && kubernetesConfig.ServiceCIDR != util.DefaultServiceCIDR {
+		flagVals = append(flagVals, "--service-cluster-ip-range="+kubernetesConfig.ServiceCIDR)
+	}
+
 	if kubernetesConfig.NodeIP != "127.0.0.1" {
 		flagVals = append(flagVals, "--node-ip="+kubernetesConfig.NodeIP)
 	}
@@ -185,31 +192,38 @@ func GenLocalkubeStartCmd(kubernetesConfig config.KubernetesConfig) (string, err
 }
 
 const logsTemplate = "if [[ `systemctl` =~ -\\.mount ]] &>/dev/null; " + `then
-  sudo journalctl {{.Flags}} -u localkube
+  sudo journalctl {{.JournalFlags}} -u localkube
 else
-  tail -n +1 {{.Flags}} {{.RemoteLocalkubeErrPath}} {{.RemoteLocalkubeOutPath}} 
+  tail {{.TailFlags}} {{.RemoteLocalkubeErrPath}} {{.RemoteLocalkubeOutPath}} 
 fi
 `
 
-func GetLogsCommand(follow bool) (string, error) {
+// GetLogsCommand returns the command printing the localkube logs. Without
+// systemd, the logs are read from files which can't be filtered by time.
+func GetLogsCommand(opts bootstrapper.LogOptions) (string, error) {
 	t, err := template.New("logsTemplate").Parse(logsTemplate)
 	if err != nil {
 		return "", err
 	}
-	var flags []string
-	if follow {
-		flags = append(flags, "-f")
+	tailFlags := []string{"-n +1"}
+	if opts.Tail > 0 {
+		tailFlags = []string{fmt.Sprintf("-n %d", opts.Tail)}
+	}
+	if opts.Follow {
+		tailFlags = append(tailFlags, "-f")
 	}
 
 	buf := bytes.Buffer{}
 	data := struct {
 		RemoteLocalkubeErrPath string
 		RemoteLocalkubeOutPath string
-		Flags                  string
+		JournalFlags           string
+		TailFlags              string
 	}{
 		RemoteLocalkubeErrPath: constants.RemoteLocalKubeErrPath,
 		RemoteLocalkubeOutPath: constants.RemoteLocalKubeOutPath,
-		Flags: strings.Join(flags, " "),
+		JournalFlags:           strings.Join(bootstrapper.JournalFlags(opts), " "),
+		TailFlags:              strings.Join(tailFlags, " "),
 	}
 	if err := t.Execute(&buf, data); err != nil {
 		return "", err
@@ -227,3 +241,27 @@ else
   fi
 fi
 `, constants.LocalkubePIDPath)
+
+// extraConfigComponents are the components localkube applies extra config to
+var extraConfigComponents = []string{"apiserver", "controller-manager", "etcd", "kubelet", "proxy", "scheduler"}
+
+// ValidateExtraOptions checks the components of the extra options. Their keys
+// are fields of the component configs, which only localkube itself can check.
+func ValidateExtraOptions(k8s config.KubernetesConfig) ([]string, error) {
+	m := util.MultiError{}
+	for _, opt := range k8s.ExtraOptions {
+		known := false
+		for _, c := range extraConfigComponents {
+			known = known || c == opt.Component
+		}
+		if known {
+			continue
+		}
+		msg := fmt.Sprintf("unknown component %q, valid components are: %s", opt.Component, strings.Join(extraConfigComponents, ", "))
+		if s, ok := util.Suggest(opt.Component, extraConfigComponents); ok {
+			msg += fmt.Sprintf(". Did you mean %q?", s)
+		}
+		m.Collect(errors.New(msg))
+	}
+	return nil, m.ToError()
+}
@@ -72,3 +72,18 @@ func flagMapToSetFlags(flagMap map[string]string) {
 func getSingleFlagValue(flag, val string) string {
 	return fmt.Sprintf("--%s %s", flag, val)
 }
+
+func TestValidateExtraOptions(t *testing.T) {
+	k8s := config.KubernetesConfig{
+		ExtraOptions: util.ExtraOptionSlice{
+			{Component: "kubelet", Key: "MaxPods", Value: "5"},
+			{Component: "etcd", Key: "Name", Value: "minikube"},
+			{Component: "shceduler", Key: "LeaderElection.LeaderElect", Value: "true"},
+		},
+	}
+	_, err := ValidateExtraOptions(k8s)
+	expected := `unknown component "shceduler", valid components are: apiserver, controller-manager, etcd, kubelet, proxy, scheduler. Did you mean "scheduler"?`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+}
@@ -17,9 +17,7 @@ limitations under the License.
 package localkube
 
 import (
-	"fmt"
 	"io"
-	"strings"
 
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
@@ -30,6 +28,7 @@ import (
 
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 )
 
@@ -58,43 +57,39 @@ func NewLocalkubeBootstrapper(api libmachine.API) (*LocalkubeBootstrapper, error
 	}, nil
 }
 
-// GetClusterLogs
-// If follow is specified, it will tail the logs
-func (lk *LocalkubeBootstrapper) GetClusterLogsTo(follow bool, out io.Writer) error {
-	logsCommand, err := GetLogsCommand(follow)
+// GetClusterLogsTo prints the logs of localkube, which runs all of the cluster
+// components in a single process.
+func (lk *LocalkubeBootstrapper) GetClusterLogsTo(k8s config.KubernetesConfig, opts bootstrapper.LogOptions, out io.Writer) error {
+	for _, c := range opts.Components {
+		if c != bootstrapper.Localkube {
+			return errors.Errorf("localkube runs all components in one process, logs are only available for %s", bootstrapper.Localkube)
+		}
+	}
+	logsCommand, err := GetLogsCommand(opts)
 	if err != nil {
 		return errors.Wrap(err, "Error getting logs command")
 	}
+	source := bootstrapper.LogSource{Component: bootstrapper.Localkube, Command: logsCommand}
+	return bootstrapper.WriteLogs(lk.cmd, []bootstrapper.LogSource{source}, opts, out)
+}
 
-	if follow {
-		err = lk.cmd.CombinedOutputTo(logsCommand, out)
-		if err != nil {
-			return errors.Wrap(err, "getting cluster logs")
-		}
-	} else {
-		logs, err := lk.cmd.CombinedOutput(logsCommand)
-		if err != nil {
-			return errors.Wrap(err, "getting cluster logs")
-		}
-		fmt.Fprint(out, logs)
-	}
-	return nil
+// GetClusterStatus gets the status of localkube and the components it runs from the host VM.
+func (lk *LocalkubeBootstrapper) GetClusterStatus(k8s config.KubernetesConfig) (bootstrapper.ClusterStatus, error) {
+	return bootstrapper.CheckHealth(lk.cmd, healthChecks(k8s)), nil
 }
 
-// GetClusterStatus gets the status of localkube from the host VM.
-func (lk *LocalkubeBootstrapper) GetClusterStatus() (string, error) {
-	s, err := lk.cmd.CombinedOutput(localkubeStatusCommand)
-	if err != nil {
-		return "", err
-	}
-	s = strings.TrimSpace(s)
-	if state.Running.String() == s {
-		return state.Running.String(), nil
-	} else if state.Stopped.String() == s {
-		return state.Stopped.String(), nil
-	} else {
-		return "", fmt.Errorf("Error: Unrecognize output from GetLocalkubeStatus: %s", s)
+func healthChecks(k8s config.KubernetesConfig) []bootstrapper.HealthCheck {
+	localkube := bootstrapper.HealthCheck{
+		Name:    bootstrapper.Localkube,
+		Command: localkubeStatusCommand,
+		Expect:  state.Running.String(),
 	}
+	return append([]bootstrapper.HealthCheck{localkube}, bootstrapper.ControlPlaneHealthChecks(bootstrapper.Localkube, k8s)...)
+}
+
+// UpgradeCluster isn't supported by localkube, which is replaced on start.
+func (lk *LocalkubeBootstrapper) UpgradeCluster(current, target config.KubernetesConfig) error {
+	return errors.New("upgrading is only supported with the kubeadm bootstrapper")
 }
 
 // StartCluster starts a k8s cluster on the specified Host.
@@ -137,11 +132,17 @@ func (lk *LocalkubeBootstrapper) UpdateCluster(config config.KubernetesConfig) e
 		return errors.Wrap(err, "adding minikube dir assets")
 	}
 	// bundled addons
-	for _, addonBundle := range assets.Addons {
+	for addonName, addonBundle := range assets.Addons {
+		if !addonBundle.SupportsKubernetesVersion(config.KubernetesVersion) {
+			glog.Warningf("Skipping addon %s, it does not support kubernetes version %s", addonName, config.KubernetesVersion)
+			continue
+		}
 		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
-			for _, addon := range addonBundle.Assets {
-				copyableFiles = append(copyableFiles, addon)
+			addonFiles, err := addonBundle.RenderAssets()
+			if err != nil {
+				return errors.Wrap(err, "rendering addon assets")
 			}
+			copyableFiles = append(copyableFiles, addonFiles...)
 		} else if err != nil {
 			return err
 		}
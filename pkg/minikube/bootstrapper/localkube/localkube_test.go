@@ -120,31 +120,50 @@ func TestUpdateCluster(t *testing.T) {
 }
 
 func TestGetLocalkubeStatus(t *testing.T) {
+	checks := healthChecks(config.KubernetesConfig{})
+	healthy := func(overrides map[string]string) map[string]string {
+		m := map[string]string{}
+		for _, c := range checks {
+			m[c.Command] = c.Expect
+		}
+		for k, v := range overrides {
+			m[k] = v
+		}
+		return m
+	}
+	apiserverCmd := checks[1].Command
+
 	cases := []struct {
 		description    string
 		statusCmdMap   map[string]string
 		expectedStatus string
-		shouldErr      bool
+		componentState map[string]string
 	}{
 		{
 			description:    "get status running",
-			statusCmdMap:   map[string]string{localkubeStatusCommand: "Running"},
+			statusCmdMap:   healthy(nil),
 			expectedStatus: "Running",
+			componentState: map[string]string{bootstrapper.APIServer: "Running", bootstrapper.DNS: "Running"},
 		},
 		{
 			description:    "get status stopped",
 			statusCmdMap:   map[string]string{localkubeStatusCommand: "Stopped"},
 			expectedStatus: "Stopped",
+			componentState: map[string]string{bootstrapper.Localkube: "Stopped", bootstrapper.Etcd: "Stopped"},
 		},
 		{
-			description:  "get status unknown status",
-			statusCmdMap: map[string]string{localkubeStatusCommand: "Recalculating..."},
-			shouldErr:    true,
+			description:    "get status unknown status",
+			statusCmdMap:   healthy(map[string]string{localkubeStatusCommand: "Recalculating..."}),
+			expectedStatus: "Error",
+			componentState: map[string]string{bootstrapper.Localkube: "Error", bootstrapper.APIServer: "Unknown"},
 		},
 		{
-			description:  "get status error",
-			statusCmdMap: map[string]string{"a": "b"},
-			shouldErr:    true,
+			description: "get status apiserver down",
+			statusCmdMap: healthy(map[string]string{
+				apiserverCmd: "curl: (7) Failed to connect to localhost port 8443: Connection refused",
+			}),
+			expectedStatus: "Error",
+			componentState: map[string]string{bootstrapper.Localkube: "Running", bootstrapper.APIServer: "Stopped", bootstrapper.Etcd: "Stopped"},
 		},
 	}
 
@@ -154,28 +173,36 @@ func TestGetLocalkubeStatus(t *testing.T) {
 			f := bootstrapper.NewFakeCommandRunner()
 			f.SetCommandToOutput(test.statusCmdMap)
 			l := LocalkubeBootstrapper{f}
-			actualStatus, err := l.GetClusterStatus()
-			if err != nil && !test.shouldErr {
-				t.Errorf("Error getting localkube status: %s", err)
-				return
+			actualStatus, err := l.GetClusterStatus(config.KubernetesConfig{})
+			if err != nil {
+				t.Fatalf("Error getting localkube status: %s", err)
 			}
-			if err == nil && test.shouldErr {
-				t.Error("Didn't get error, but expected to")
-				return
+			if test.expectedStatus != actualStatus.State {
+				t.Errorf("Expected status: %s, Actual status: %s", test.expectedStatus, actualStatus.State)
 			}
-			if test.expectedStatus != actualStatus {
-				t.Errorf("Expected status: %s, Actual status: %s", test.expectedStatus, actualStatus)
+			if len(actualStatus.Components) != len(checks) {
+				t.Errorf("Expected %d components, got %+v", len(checks), actualStatus.Components)
+			}
+			for name, expected := range test.componentState {
+				c, ok := actualStatus.Component(name)
+				if !ok {
+					t.Errorf("Component %s missing from status", name)
+					continue
+				}
+				if c.State != expected {
+					t.Errorf("Expected %s to be %s, got %s: %s", name, expected, c.State, c.Error)
+				}
 			}
 		})
 	}
 }
 
 func TestGetHostLogs(t *testing.T) {
-	logs, err := GetLogsCommand(false)
+	logs, err := GetLogsCommand(bootstrapper.LogOptions{})
 	if err != nil {
 		t.Fatalf("Error getting logs command: %s", err)
 	}
-	logsf, err := GetLogsCommand(true)
+	logsf, err := GetLogsCommand(bootstrapper.LogOptions{Follow: true})
 	if err != nil {
 		t.Fatalf("Error gettings logs -f command: %s", err)
 	}
@@ -184,6 +211,7 @@ func TestGetHostLogs(t *testing.T) {
 		description string
 		logsCmdMap  map[string]string
 		follow      bool
+		components  []string
 		shouldErr   bool
 	}{
 		{
@@ -200,6 +228,17 @@ func TestGetHostLogs(t *testing.T) {
 			logsCmdMap:  map[string]string{"fo": "fum"},
 			shouldErr:   true,
 		},
+		{
+			description: "get localkube logs",
+			logsCmdMap:  map[string]string{logs: "fee"},
+			components:  []string{"localkube"},
+		},
+		{
+			description: "get apiserver logs",
+			logsCmdMap:  map[string]string{logs: "fee"},
+			components:  []string{"apiserver"},
+			shouldErr:   true,
+		},
 	}
 
 	var b bytes.Buffer
@@ -209,7 +248,8 @@ func TestGetHostLogs(t *testing.T) {
 			f := bootstrapper.NewFakeCommandRunner()
 			f.SetCommandToOutput(test.logsCmdMap)
 			l := LocalkubeBootstrapper{f}
-			err := l.GetClusterLogsTo(test.follow, &b)
+			opts := bootstrapper.LogOptions{Components: test.components, Follow: test.follow}
+			err := l.GetClusterLogsTo(config.KubernetesConfig{}, opts, &b)
 			if err != nil && !test.shouldErr {
 				t.Errorf("Error getting localkube logs: %s", err)
 				return
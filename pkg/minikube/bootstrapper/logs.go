@@ -0,0 +1,257 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LogOptions selects the cluster logs returned by GetClusterLogsTo
+type LogOptions struct {
+	// Components to return the logs of, all of them if empty
+	Components []string
+	// Since only returns entries newer than this, all entries if zero
+	Since time.Duration
+	// Tail only returns the last Tail entries, all entries if zero
+	Tail int
+	// Follow keeps printing new entries as they are logged
+	Follow bool
+	// Prefix prefixes every line with its component, even when only one
+	// source is printed
+	Prefix bool
+}
+
+// LogSource is a command printing the logs of a component
+type LogSource struct {
+	Component string
+	Command   string
+}
+
+// JournalFlags returns the journalctl flags selecting the logs of opts
+func JournalFlags(opts LogOptions) []string {
+	flags := []string{"--no-pager", "-q", "-o", "short-iso"}
+	if opts.Since > 0 {
+		flags = append(flags, fmt.Sprintf("--since=-%ds", int64(opts.Since.Seconds())))
+	}
+	if opts.Tail > 0 {
+		flags = append(flags, fmt.Sprintf("-n %d", opts.Tail))
+	}
+	if opts.Follow {
+		flags = append(flags, "-f")
+	}
+	return flags
+}
+
+// JournalLogSource returns the logs of a systemd unit
+func JournalLogSource(component, unit string, opts LogOptions) LogSource {
+	return LogSource{
+		Component: component,
+		Command:   fmt.Sprintf("sudo journalctl %s -u %s", strings.Join(JournalFlags(opts), " "), unit),
+	}
+}
+
+// ContainerLogSources returns the logs of every container, including exited
+// ones, of a kubernetes container name. The containers are looked up through
+// the container runtime of the node.
+func ContainerLogSources(cmd CommandRunner, runtime, component, container string, opts LogOptions) ([]LogSource, error) {
+	var psCmd, logsCmd string
+	switch runtime {
+	case "cri-o", "crio", "cri":
+		psCmd = fmt.Sprintf("sudo crictl ps -a -q --name %s", container)
+		logsCmd = "sudo crictl logs --timestamps"
+	default:
+		psCmd = fmt.Sprintf("docker ps -a -q --filter name=k8s_%s_", container)
+		logsCmd = "docker logs --timestamps"
+	}
+
+	out, err := cmd.CombinedOutput(psCmd)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing %s containers: %s", container, out)
+	}
+
+	var flags []string
+	if opts.Since > 0 {
+		flags = append(flags, fmt.Sprintf("--since=%s", opts.Since))
+	}
+	if opts.Tail > 0 {
+		flags = append(flags, fmt.Sprintf("--tail=%d", opts.Tail))
+	}
+	if opts.Follow {
+		flags = append(flags, "-f")
+	}
+	var sources []LogSource
+	for _, id := range strings.Fields(out) {
+		sources = append(sources, LogSource{
+			Component: component,
+			Command:   strings.Join(append(append([]string{logsCmd}, flags...), id), " "),
+		})
+	}
+	return sources, nil
+}
+
+// WriteLogs prints the logs of the sources to out. The logs of a single
+// source are printed as they are, logs of several sources are prefixed with
+// their component and interleaved by timestamp. Sources which fail are
+// skipped, and their errors returned once the others are printed.
+func WriteLogs(cmd CommandRunner, sources []LogSource, opts LogOptions, out io.Writer) error {
+	switch {
+	case len(sources) == 0:
+		return nil
+	case len(sources) == 1 && opts.Follow && !opts.Prefix:
+		return errors.Wrap(cmd.CombinedOutputTo(sources[0].Command, out), "getting cluster logs")
+	case len(sources) == 1 && !opts.Prefix:
+		logs, err := cmd.CombinedOutput(sources[0].Command)
+		if err != nil {
+			return errors.Wrap(err, "getting cluster logs")
+		}
+		fmt.Fprint(out, logs)
+		return nil
+	case opts.Follow:
+		return followLogs(cmd, sources, out)
+	}
+
+	var entries []logEntry
+	var failures []string
+	for _, s := range sources {
+		logs, err := cmd.CombinedOutput(s.Command)
+		if err != nil {
+			failures = append(failures, errors.Wrapf(err, "getting %s logs", s.Component).Error())
+			continue
+		}
+		entries = append(entries, parseLogEntries(s.Component, logs)...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].time.Before(entries[j].time)
+	})
+	if opts.Tail > 0 && len(entries) > opts.Tail {
+		entries = entries[len(entries)-opts.Tail:]
+	}
+	for _, e := range entries {
+		fmt.Fprintf(out, "[%s] %s\n", e.component, e.line)
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+// maxLogLineSize is the longest log line which can be parsed
+const maxLogLineSize = 1024 * 1024
+
+// logEntry is a single line of a component's logs
+type logEntry struct {
+	time      time.Time
+	component string
+	line      string
+}
+
+// logTimeFormats are the timestamp formats of docker/crictl and journalctl
+var logTimeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05-0700",
+}
+
+// parseLogEntries splits logs into lines, lines without a timestamp, e.g.
+// stack traces, are given the timestamp of the line before them.
+func parseLogEntries(component, logs string) []logEntry {
+	var entries []logEntry
+	var last time.Time
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	scanner.Buffer(nil, maxLogLineSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		stamp := strings.SplitN(line, " ", 2)[0]
+		for _, f := range logTimeFormats {
+			if t, err := time.Parse(f, stamp); err == nil {
+				last = t
+				break
+			}
+		}
+		entries = append(entries, logEntry{time: last, component: component, line: line})
+	}
+	return entries
+}
+
+// followLogs streams the logs of all sources at once until they all exit
+func followLogs(cmd CommandRunner, sources []LogSource, out io.Writer) error {
+	var mu sync.Mutex
+	errs := make(chan error, len(sources))
+	for _, s := range sources {
+		go func(s LogSource) {
+			w := &prefixWriter{prefix: fmt.Sprintf("[%s] ", s.Component), out: out, mu: &mu}
+			err := cmd.CombinedOutputTo(s.Command, w)
+			w.flush()
+			errs <- errors.Wrapf(err, "following %s logs", s.Component)
+		}(s)
+	}
+	var err error
+	for range sources {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// prefixWriter prefixes every complete line written to it, writes to out are
+// serialized by mu so lines of different writers don't get mixed up.
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	mu     *sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.buf.Next(i + 1)
+		if err := w.writeLine(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (w *prefixWriter) flush() {
+	if w.buf.Len() > 0 {
+		w.writeLine(append(w.buf.Bytes(), '\n'))
+		w.buf.Reset()
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+	return err
+}
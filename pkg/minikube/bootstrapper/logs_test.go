@@ -0,0 +1,200 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournalLogSource(t *testing.T) {
+	s := JournalLogSource(Kubelet, "kubelet", LogOptions{Since: time.Hour, Tail: 10, Follow: true})
+	expected := "sudo journalctl --no-pager -q -o short-iso --since=-3600s -n 10 -f -u kubelet"
+	if s.Command != expected {
+		t.Errorf("Expected command %q, got %q", expected, s.Command)
+	}
+}
+
+func TestContainerLogSources(t *testing.T) {
+	tests := []struct {
+		description string
+		runtime     string
+		outputs     map[string]string
+		opts        LogOptions
+		expected    []string
+		shouldErr   bool
+	}{
+		{
+			description: "docker",
+			outputs:     map[string]string{"docker ps -a -q --filter name=k8s_etcd_": "abc\ndef\n"},
+			opts:        LogOptions{Since: 5 * time.Minute, Tail: 20},
+			expected: []string{
+				"docker logs --timestamps --since=5m0s --tail=20 abc",
+				"docker logs --timestamps --since=5m0s --tail=20 def",
+			},
+		},
+		{
+			description: "crio",
+			runtime:     "crio",
+			outputs:     map[string]string{"sudo crictl ps -a -q --name etcd": "abc\n"},
+			opts:        LogOptions{Follow: true},
+			expected:    []string{"sudo crictl logs --timestamps -f abc"},
+		},
+		{
+			description: "no containers",
+			outputs:     map[string]string{"docker ps -a -q --filter name=k8s_etcd_": ""},
+		},
+		{
+			description: "runtime error",
+			outputs:     map[string]string{},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			f := NewFakeCommandRunner()
+			f.SetCommandToOutput(test.outputs)
+			sources, err := ContainerLogSources(f, test.runtime, Etcd, "etcd", test.opts)
+			if err != nil {
+				if !test.shouldErr {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if test.shouldErr {
+				t.Fatal("Expected an error")
+			}
+			var commands []string
+			for _, s := range sources {
+				if s.Component != Etcd {
+					t.Errorf("Expected component %s, got %s", Etcd, s.Component)
+				}
+				commands = append(commands, s.Command)
+			}
+			if !reflect.DeepEqual(commands, test.expected) {
+				t.Errorf("Expected commands %v, got %v", test.expected, commands)
+			}
+		})
+	}
+}
+
+func TestWriteLogs(t *testing.T) {
+	sources := []LogSource{
+		{Component: Kubelet, Command: "kubelet logs"},
+		{Component: APIServer, Command: "apiserver logs"},
+	}
+	f := NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"kubelet logs": "2018-05-01T10:00:01+0000 minikube kubelet[1]: started\n" +
+			"2018-05-01T10:00:03+0000 minikube kubelet[1]: synced\n",
+		"apiserver logs": "2018-05-01T10:00:02.000000001Z E0501 panic\n" +
+			"goroutine 1 [running]:\n" +
+			"2018-05-01T10:00:04Z I0501 serving\n",
+	})
+
+	tests := []struct {
+		description string
+		sources     []LogSource
+		opts        LogOptions
+		expected    string
+	}{
+		{
+			description: "single source",
+			sources:     sources[:1],
+			expected: "2018-05-01T10:00:01+0000 minikube kubelet[1]: started\n" +
+				"2018-05-01T10:00:03+0000 minikube kubelet[1]: synced\n",
+		},
+		{
+			description: "single source prefixed",
+			sources:     sources[:1],
+			opts:        LogOptions{Prefix: true},
+			expected: "[kubelet] 2018-05-01T10:00:01+0000 minikube kubelet[1]: started\n" +
+				"[kubelet] 2018-05-01T10:00:03+0000 minikube kubelet[1]: synced\n",
+		},
+		{
+			description: "interleaved",
+			sources:     sources,
+			expected: "[kubelet] 2018-05-01T10:00:01+0000 minikube kubelet[1]: started\n" +
+				"[apiserver] 2018-05-01T10:00:02.000000001Z E0501 panic\n" +
+				"[apiserver] goroutine 1 [running]:\n" +
+				"[kubelet] 2018-05-01T10:00:03+0000 minikube kubelet[1]: synced\n" +
+				"[apiserver] 2018-05-01T10:00:04Z I0501 serving\n",
+		},
+		{
+			description: "interleaved tail",
+			sources:     sources,
+			opts:        LogOptions{Tail: 2},
+			expected: "[kubelet] 2018-05-01T10:00:03+0000 minikube kubelet[1]: synced\n" +
+				"[apiserver] 2018-05-01T10:00:04Z I0501 serving\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := WriteLogs(f, test.sources, test.opts, &b); err != nil {
+				t.Fatalf("Error writing logs: %s", err)
+			}
+			if b.String() != test.expected {
+				t.Errorf("Expected logs:\n%s\ngot:\n%s", test.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestWriteLogsPartial(t *testing.T) {
+	f := NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"kubelet logs": "2018-05-01T10:00:01+0000 minikube kubelet[1]: started\n",
+	})
+	sources := []LogSource{
+		{Component: Kubelet, Command: "kubelet logs"},
+		{Component: APIServer, Command: "apiserver logs"},
+	}
+	var b bytes.Buffer
+	if err := WriteLogs(f, sources, LogOptions{}, &b); err == nil {
+		t.Error("Expected an error for the apiserver logs")
+	}
+	if expected := "[kubelet] 2018-05-01T10:00:01+0000 minikube kubelet[1]: started\n"; b.String() != expected {
+		t.Errorf("Expected logs:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
+
+func TestFollowLogs(t *testing.T) {
+	f := NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"kubelet logs":   "started\nsynced",
+		"apiserver logs": "serving\n",
+	})
+	sources := []LogSource{
+		{Component: Kubelet, Command: "kubelet logs"},
+		{Component: APIServer, Command: "apiserver logs"},
+	}
+	var b bytes.Buffer
+	if err := WriteLogs(f, sources, LogOptions{Follow: true}, &b); err != nil {
+		t.Fatalf("Error following logs: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	sort.Strings(lines)
+	expected := []string{"[apiserver] serving", "[kubelet] started", "[kubelet] synced"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected lines %v, got %v", expected, lines)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// LogProblem is the signature of a known failure in the cluster logs
+type LogProblem struct {
+	Name   string
+	Regexp *regexp.Regexp
+	// Advice is the suggested remedy for the problem
+	Advice string
+}
+
+// LogProblems are the known failures looked for by FindProblems
+var LogProblems = []*LogProblem{
+	{
+		Name:   "image pull failure",
+		Regexp: regexp.MustCompile(`ErrImagePull|ImagePullBackOff|[Ff]ailed to pull image|pull access denied`),
+		Advice: "An image could not be downloaded. Check that the VM can reach the registry, see docs/http_proxy.md if you are behind a proxy, or add the image to the cache with `minikube cache add`.",
+	},
+	{
+		Name:   "cgroup driver mismatch",
+		Regexp: regexp.MustCompile(`cgroup driver: "\w+" is different from docker cgroup driver`),
+		Advice: "The kubelet and the container runtime use different cgroup drivers. Restart with --extra-config=kubelet.cgroup-driver set to the driver of the container runtime.",
+	},
+	{
+		Name:   "swap is on",
+		Regexp: regexp.MustCompile(`(?i)running with swap on is not supported`),
+		Advice: "The kubelet refuses to run with swap enabled. Turn it off with `sudo swapoff -a`, or restart with --extra-config=kubelet.fail-swap-on=false.",
+	},
+	{
+		Name:   "port in use",
+		Regexp: regexp.MustCompile(`address already in use|[Pp]ort \d+ is in use`),
+		Advice: "Another process is listening on a port used by the cluster. With the none driver, stop any other kubernetes cluster or service using the port.",
+	},
+	{
+		Name:   "certificate error",
+		Regexp: regexp.MustCompile(`x509: `),
+		Advice: "The cluster certificates are invalid or don't match. Restart the cluster with `minikube stop && minikube start` to regenerate them, or run `minikube delete` if the problem persists.",
+	},
+	{
+		Name:   "etcd disk pressure",
+		Regexp: regexp.MustCompile(`mvcc: database space exceeded|wal: sync duration of|apply entries took too long`),
+		Advice: "etcd is running out of disk space or its disk is too slow. Free up space on the host, or recreate the cluster with a larger --disk-size.",
+	},
+}
+
+// ProblemMatch is a line of the cluster logs matching a known problem
+type ProblemMatch struct {
+	Component string
+	Problem   *LogProblem
+	Line      string
+}
+
+// FindProblems returns the lines of the cluster logs selected by opts which
+// match a known problem. When some logs can't be read, the problems of the
+// others are returned along with the error.
+func FindProblems(b Bootstrapper, k8s config.KubernetesConfig, opts LogOptions) ([]ProblemMatch, error) {
+	opts.Follow = false
+	opts.Prefix = true
+	var logs bytes.Buffer
+	err := b.GetClusterLogsTo(k8s, opts, &logs)
+	matches := MatchProblems(&logs)
+	if err != nil {
+		return matches, errors.Wrap(err, "getting cluster logs")
+	}
+	return matches, nil
+}
+
+// componentPrefix matches the component prefix added to interleaved logs
+var componentPrefix = regexp.MustCompile(`^\[([\w-]+)\] `)
+
+// MatchProblems returns the lines of component prefixed logs which match a
+// known problem.
+func MatchProblems(logs io.Reader) []ProblemMatch {
+	var matches []ProblemMatch
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(nil, maxLogLineSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		component := ""
+		if m := componentPrefix.FindStringSubmatch(line); m != nil {
+			component = m[1]
+			line = line[len(m[0]):]
+		}
+		for _, p := range LogProblems {
+			if p.Regexp.MatchString(line) {
+				matches = append(matches, ProblemMatch{Component: component, Problem: p, Line: line})
+				break
+			}
+		}
+	}
+	return matches
+}
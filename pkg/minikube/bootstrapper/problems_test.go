@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchProblems(t *testing.T) {
+	tests := []struct {
+		line      string
+		component string
+		problem   string
+	}{
+		{
+			line:      `[kubelet] May 01 kubelet[1]: E0501 pod_workers.go:186] Error syncing pod: ErrImagePull: "rpc error: code = Unknown desc = Error response from daemon: pull access denied for foo"`,
+			component: "kubelet",
+			problem:   "image pull failure",
+		},
+		{
+			line:      `[kubelet] May 01 kubelet[1]: F0501 server.go:233] failed to run Kubelet: failed to create kubelet: misconfiguration: kubelet cgroup driver: "cgroupfs" is different from docker cgroup driver: "systemd"`,
+			component: "kubelet",
+			problem:   "cgroup driver mismatch",
+		},
+		{
+			line:      `[localkube] May 01 localkube[1]: F0501 server.go:233] failed to run Kubelet: Running with swap on is not supported, please disable swap!`,
+			component: "localkube",
+			problem:   "swap is on",
+		},
+		{
+			line:      `[apiserver] 2018-05-01T10:00:00Z F0501 server.go:145] listen tcp 0.0.0.0:8443: bind: address already in use`,
+			component: "apiserver",
+			problem:   "port in use",
+		},
+		{
+			line:      `[controller-manager] 2018-05-01T10:00:00Z E0501 reflector.go:205] Get https://192.168.99.100:8443/api/v1/nodes: x509: certificate signed by unknown authority`,
+			component: "controller-manager",
+			problem:   "certificate error",
+		},
+		{
+			line:      `[etcd] 2018-05-01T10:00:00Z 2018-05-01 10:00:00.000 W | etcdserver: apply entries took too long [1.5s for 1 entries]`,
+			component: "etcd",
+			problem:   "etcd disk pressure",
+		},
+		{
+			line:    `May 01 kubelet[1]: E0501 remote_image.go:108] PullImage "foo" from image service failed: Failed to pull image "foo"`,
+			problem: "image pull failure",
+		},
+		{
+			line: `[kubelet] May 01 kubelet[1]: I0501 kubelet.go:1850] SyncLoop (ADD, "api"): "kube-dns"`,
+		},
+	}
+	for _, test := range tests {
+		matches := MatchProblems(strings.NewReader(test.line))
+		if test.problem == "" {
+			if len(matches) != 0 {
+				t.Errorf("Expected no problems in %q, got %+v", test.line, matches)
+			}
+			continue
+		}
+		if len(matches) != 1 {
+			t.Errorf("Expected one problem in %q, got %+v", test.line, matches)
+			continue
+		}
+		m := matches[0]
+		if m.Problem.Name != test.problem || m.Component != test.component {
+			t.Errorf("Expected %s in %s, got %s in %s", test.problem, test.component, m.Problem.Name, m.Component)
+		}
+		if strings.HasPrefix(m.Line, "[") {
+			t.Errorf("Expected the component prefix to be removed from %q", m.Line)
+		}
+	}
+}
@@ -26,6 +26,8 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/docker/machine/libmachine"
@@ -38,6 +40,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	dockerdriver "k8s.io/minikube/pkg/drivers/docker"
+	"k8s.io/minikube/pkg/drivers/qemu"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/registry"
@@ -84,13 +89,25 @@ func StartHost(api libmachine.API, config cfg.MachineConfig) (*host.Host, error)
 		return nil, errors.Wrap(err, "Error getting state for host")
 	}
 
+	diskGrown := false
 	if s != state.Running {
+		if err := updateResources(h, config); err != nil {
+			return nil, errors.Wrap(err, "Error updating cpus and memory")
+		}
+		diskGrown, err = updateDiskSize(h, config)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error updating disk size")
+		}
 		if err := h.Driver.Start(); err != nil {
 			return nil, errors.Wrap(err, "Error starting stopped host")
 		}
 		if err := api.Save(h); err != nil {
 			return nil, errors.Wrap(err, "Error saving started host")
 		}
+	} else if driverConfig, err := getDriverConfig(h); err == nil {
+		if changes := resourceChanges(driverConfig, config); len(changes) > 0 {
+			fmt.Printf("The VM is running, stop it with `minikube stop` to apply these changes: %s\n", formatChanges(changes))
+		}
 	}
 
 	if h.Driver.DriverName() != "none" {
@@ -98,9 +115,155 @@ func StartHost(api libmachine.API, config cfg.MachineConfig) (*host.Host, error)
 			return nil, &util.RetriableError{Err: errors.Wrap(err, "Error configuring auth on host")}
 		}
 	}
+	if diskGrown {
+		glog.Infoln("Growing the data partition to fill the disk")
+		if out, err := h.RunSSHCommand(pkgdrivers.GrowDataPartitionCmd); err != nil {
+			glog.Warningf("Unable to grow the data partition: %s: %s", err, out)
+			fmt.Println("The disk was resized but the filesystem could not be grown, see the logs for details.")
+		}
+	}
 	return h, nil
 }
 
+// rawConfigDriver is implemented by plugin drivers, whose config can be
+// updated after the machine is created.
+type rawConfigDriver interface {
+	GetConfigRaw() ([]byte, error)
+	SetConfigRaw([]byte) error
+}
+
+// resizableDrivers are the drivers applying changes to the cpus and memory of
+// a stopped machine when it is started.
+var resizableDrivers = map[string]bool{
+	"kvm2":     true,
+	"hyperkit": true,
+}
+
+// resourceChange is a change of a machine resource, such as its memory
+type resourceChange struct {
+	name     string
+	key      string
+	unit     string
+	old, new int
+}
+
+func (c resourceChange) String() string {
+	return fmt.Sprintf("%s %d%s -> %d%s", c.name, c.old, c.unit, c.new, c.unit)
+}
+
+func formatChanges(changes []resourceChange) string {
+	var s []string
+	for _, c := range changes {
+		s = append(s, c.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// getDriverConfig returns the config of the machine driver
+func getDriverConfig(h *host.Host) (map[string]interface{}, error) {
+	var raw []byte
+	var err error
+	if d, ok := h.Driver.(rawConfigDriver); ok {
+		raw, err = d.GetConfigRaw()
+	} else {
+		raw, err = json.Marshal(h.Driver)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "getting driver config")
+	}
+	var driverConfig map[string]interface{}
+	if err := json.Unmarshal(raw, &driverConfig); err != nil {
+		return nil, errors.Wrap(err, "parsing driver config")
+	}
+	return driverConfig, nil
+}
+
+// setDriverConfig updates the config of a plugin driver
+func setDriverConfig(h *host.Host, driverConfig map[string]interface{}) error {
+	d, ok := h.Driver.(rawConfigDriver)
+	if !ok {
+		return errors.Errorf("the config of the %s driver can't be updated", h.DriverName)
+	}
+	raw, err := json.Marshal(driverConfig)
+	if err != nil {
+		return errors.Wrap(err, "encoding driver config")
+	}
+	return errors.Wrap(d.SetConfigRaw(raw), "setting driver config")
+}
+
+// resourceChanges returns how the cpus and memory in config differ from the
+// ones the machine was created with.
+func resourceChanges(driverConfig map[string]interface{}, config cfg.MachineConfig) []resourceChange {
+	var changes []resourceChange
+	for _, c := range []resourceChange{
+		{name: "cpus", key: "CPU", new: config.CPUs},
+		{name: "memory", key: "Memory", unit: "MB", new: config.Memory},
+	} {
+		current, ok := driverConfig[c.key].(float64)
+		if !ok || c.new == 0 || c.new == int(current) {
+			continue
+		}
+		c.old = int(current)
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// updateResources updates the cpus and memory of a stopped machine if they
+// were changed, for example with `minikube config set memory`. The driver
+// applies them to the VM when the machine is started.
+func updateResources(h *host.Host, config cfg.MachineConfig) error {
+	driverConfig, err := getDriverConfig(h)
+	if err != nil {
+		return err
+	}
+	changes := resourceChanges(driverConfig, config)
+	if len(changes) == 0 {
+		return nil
+	}
+	if !resizableDrivers[h.DriverName] {
+		fmt.Printf("The %s driver can't change the resources of an existing VM, run `minikube delete` to apply these changes: %s\n", h.DriverName, formatChanges(changes))
+		return nil
+	}
+	fmt.Println("Reconfiguring the VM:")
+	for _, c := range changes {
+		fmt.Printf("  %s\n", c)
+		driverConfig[c.key] = c.new
+	}
+	return setDriverConfig(h, driverConfig)
+}
+
+// updateDiskSize updates the disk size of a stopped kvm2 machine if it was
+// changed, for example with `minikube config set disk-size`. The driver grows
+// the disk image when the machine is started. It returns whether the disk
+// size was increased.
+func updateDiskSize(h *host.Host, config cfg.MachineConfig) (bool, error) {
+	if h.DriverName != "kvm2" || config.DiskSize == 0 {
+		return false, nil
+	}
+	if _, ok := h.Driver.(rawConfigDriver); !ok {
+		return false, nil
+	}
+	driverConfig, err := getDriverConfig(h)
+	if err != nil {
+		return false, err
+	}
+	current, ok := driverConfig["DiskSize"].(float64)
+	if !ok || config.DiskSize == int(current) {
+		return false, nil
+	}
+	if config.DiskSize < int(current) {
+		glog.Infof("Shrinking the disk isn't supported, keeping the current size of %dMB", int(current))
+		return false, nil
+	}
+	fmt.Printf("Resizing the disk from %dMB to %dMB...\n", int(current), config.DiskSize)
+	driverConfig["DiskSize"] = config.DiskSize
+	if err := setDriverConfig(h, driverConfig); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // StopHost stops the host VM.
 func StopHost(api libmachine.API) error {
 	host, err := api.Load(cfg.GetMachineName())
@@ -170,9 +333,13 @@ func GetHostDriverIP(api libmachine.API) (net.IP, error) {
 }
 
 func engineOptions(config cfg.MachineConfig) *engine.Options {
+	serviceCIDR := config.ServiceCIDR
+	if serviceCIDR == "" {
+		serviceCIDR = pkgutil.DefaultServiceCIDR
+	}
 	o := engine.Options{
 		Env:              config.DockerEnv,
-		InsecureRegistry: append([]string{pkgutil.DefaultServiceCIDR}, config.InsecureRegistry...),
+		InsecureRegistry: append([]string{serviceCIDR}, config.InsecureRegistry...),
 		RegistryMirror:   config.RegistryMirror,
 		ArbitraryFlags:   config.DockerOpt,
 	}
@@ -233,6 +400,18 @@ func createHost(api libmachine.API, config cfg.MachineConfig) (*host.Host, error
 		return nil, errors.Wrap(err, "Error creating new host")
 	}
 
+	if def.PluginPath != "" {
+		flags, err := registry.PluginFlags(h.Driver.GetCreateFlags(), config.DriverOptions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error configuring driver plugin %s", def.PluginPath)
+		}
+		if err := h.Driver.SetConfigFromFlags(flags); err != nil {
+			return nil, errors.Wrapf(err, "Error configuring driver plugin %s", def.PluginPath)
+		}
+	} else if len(config.DriverOptions) > 0 {
+		fmt.Printf("The %s driver doesn't take driver options, they will be ignored.\n", config.VMDriver)
+	}
+
 	h.HostOptions.AuthOptions.CertDir = constants.GetMinipath()
 	h.HostOptions.AuthOptions.StorePath = constants.GetMinipath()
 	h.HostOptions.EngineOptions = engineOptions(config)
@@ -262,10 +441,23 @@ func GetHostDockerEnv(api libmachine.API) (map[string]string, error) {
 
 	tcpPrefix := "tcp://"
 	port := "2376"
+	if d, ok := host.Driver.(*qemu.Driver); ok {
+		// The docker port of qemu VMs is forwarded to a free port on the host
+		port = strconv.Itoa(d.DockerPort)
+	}
+
+	dockerHost := tcpPrefix + net.JoinHostPort(ip, port)
+	if d, ok := host.Driver.(*dockerdriver.Driver); ok {
+		// The node's daemon is published on the host, which may not be able
+		// to reach the container network
+		if dockerHost, err = d.GetDockerHost(); err != nil {
+			return nil, errors.Wrap(err, "Error getting the docker host")
+		}
+	}
 
 	envMap := map[string]string{
 		"DOCKER_TLS_VERIFY": "1",
-		"DOCKER_HOST":       tcpPrefix + net.JoinHostPort(ip, port),
+		"DOCKER_HOST":       dockerHost,
 		"DOCKER_CERT_PATH":  constants.MakeMiniPath("certs"),
 	}
 	return envMap, nil
@@ -295,13 +487,31 @@ func MountHost(api libmachine.API, ip net.IP, path, port, mountVersion string, u
 	return nil
 }
 
+func getKvmHostIP(cidr string) (net.IP, error) {
+	n, err := pkgutil.ParsePrivateNetwork(cidr)
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "Error getting VM/Host IP address")
+	}
+	return n.Gateway, nil
+}
+
 // GetVMHostIP gets the ip address to be used for mapping host -> VM and VM -> host
 func GetVMHostIP(host *host.Host) (net.IP, error) {
 	switch host.DriverName {
 	case "kvm":
-		return net.ParseIP("192.168.42.1"), nil
+		return getKvmHostIP(constants.KvmDockerMachinesNetworkCIDR)
 	case "kvm2":
-		return net.ParseIP("192.168.39.1"), nil
+		var d struct {
+			PrivateNetworkCIDR string
+		}
+		if err := json.Unmarshal(host.RawDriver, &d); err != nil {
+			return []byte{}, errors.Wrap(err, "Error reading kvm2 driver config")
+		}
+		// Machines created before the cidr was configurable use the default
+		if d.PrivateNetworkCIDR == "" {
+			d.PrivateNetworkCIDR = constants.DefaultKvmPrivateNetworkCIDR
+		}
+		return getKvmHostIP(d.PrivateNetworkCIDR)
 	case "hyperv":
 		re := regexp.MustCompile(`"VSwitch": "(.*?)",`)
 		// TODO(aprindle) Change this to deserialize the driver instead
@@ -325,6 +535,12 @@ func GetVMHostIP(host *host.Host) (net.IP, error) {
 		return ip, nil
 	case "xhyve", "hyperkit":
 		return net.ParseIP("192.168.64.1"), nil
+	case "docker":
+		d, ok := host.Driver.(*dockerdriver.Driver)
+		if !ok {
+			return []byte{}, errors.Errorf("unexpected driver type %T for the docker driver", host.Driver)
+		}
+		return d.GetHostIP()
 	default:
 		return []byte{}, errors.New("Error, attempted to get host ip address for unsupported driver")
 	}
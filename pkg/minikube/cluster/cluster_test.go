@@ -390,3 +390,103 @@ func TestCreateSSHShell(t *testing.T) {
 		t.Fatalf("Expected ssh session to be run")
 	}
 }
+
+func TestGetVMHostIPKvm(t *testing.T) {
+	var tests = []struct {
+		driverName string
+		rawDriver  string
+		expected   string
+	}{
+		{"kvm", `{}`, "192.168.42.1"},
+		{"kvm2", `{"PrivateNetworkCIDR": "10.100.0.0/24"}`, "10.100.0.1"},
+		{"kvm2", `{}`, "192.168.39.1"},
+	}
+	for _, test := range tests {
+		h := &host.Host{DriverName: test.driverName, RawDriver: []byte(test.rawDriver)}
+		ip, err := GetVMHostIP(h)
+		if err != nil {
+			t.Fatalf("Error getting host IP: %s", err)
+		}
+		if ip.String() != test.expected {
+			t.Errorf("Expected host IP %s for %s, got %s", test.expected, test.rawDriver, ip)
+		}
+	}
+}
+
+type rawConfigMockDriver struct {
+	tests.MockDriver
+	raw []byte
+}
+
+func (d *rawConfigMockDriver) GetConfigRaw() ([]byte, error) { return d.raw, nil }
+func (d *rawConfigMockDriver) SetConfigRaw(raw []byte) error { d.raw = raw; return nil }
+
+func TestUpdateDiskSize(t *testing.T) {
+	var tests = []struct {
+		description  string
+		driverName   string
+		diskSize     int
+		expectGrown  bool
+		expectedSize string
+	}{
+		{"grow", "kvm2", 40000, true, `"DiskSize":40000`},
+		{"unchanged", "kvm2", 20000, false, `"DiskSize": 20000`},
+		{"shrink", "kvm2", 10000, false, `"DiskSize": 20000`},
+		{"other driver", "virtualbox", 40000, false, `"DiskSize": 20000`},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			d := &rawConfigMockDriver{raw: []byte(`{"DiskSize": 20000, "CPU": 2}`)}
+			h := &host.Host{DriverName: test.driverName, Driver: d}
+			grown, err := updateDiskSize(h, config.MachineConfig{DiskSize: test.diskSize})
+			if err != nil {
+				t.Fatalf("Error updating disk size: %s", err)
+			}
+			if grown != test.expectGrown {
+				t.Errorf("Expected grown to be %t, got %t", test.expectGrown, grown)
+			}
+			if !strings.Contains(string(d.raw), test.expectedSize) {
+				t.Errorf("Expected driver config to contain %s, got %s", test.expectedSize, d.raw)
+			}
+		})
+	}
+}
+
+func TestUpdateResources(t *testing.T) {
+	var tests = []struct {
+		description string
+		driverName  string
+		config      config.MachineConfig
+		expected    []string
+	}{
+		{"unchanged", "kvm2", config.MachineConfig{CPUs: 2, Memory: 2048}, []string{`"CPU": 2`, `"Memory": 2048`}},
+		{"changed", "hyperkit", config.MachineConfig{CPUs: 4, Memory: 4096}, []string{`"CPU":4`, `"Memory":4096`, `"DiskSize":20000`}},
+		{"unset", "kvm2", config.MachineConfig{}, []string{`"CPU": 2`, `"Memory": 2048`}},
+		{"other driver", "virtualbox", config.MachineConfig{CPUs: 4, Memory: 4096}, []string{`"CPU": 2`, `"Memory": 2048`}},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			d := &rawConfigMockDriver{raw: []byte(`{"DiskSize": 20000, "CPU": 2, "Memory": 2048}`)}
+			h := &host.Host{DriverName: test.driverName, Driver: d}
+			if err := updateResources(h, test.config); err != nil {
+				t.Fatalf("Error updating resources: %s", err)
+			}
+			for _, e := range test.expected {
+				if !strings.Contains(string(d.raw), e) {
+					t.Errorf("Expected driver config to contain %s, got %s", e, d.raw)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceChanges(t *testing.T) {
+	driverConfig := map[string]interface{}{"CPU": float64(2), "Memory": float64(2048)}
+	changes := resourceChanges(driverConfig, config.MachineConfig{CPUs: 2, Memory: 4096})
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %v", changes)
+	}
+	if s := formatChanges(changes); s != "memory 2048MB -> 4096MB" {
+		t.Errorf("Unexpected changes: %s", s)
+	}
+}
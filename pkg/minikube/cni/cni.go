@@ -0,0 +1,143 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cni deploys the container network interface plugins of a cluster
+package cni
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// The CNIs bundled with minikube
+const (
+	Bridge  = "bridge"
+	Flannel = "flannel"
+	Calico  = "calico"
+)
+
+// DefaultPodCIDR is the pod network of clusters with a CNI, unless another
+// one is set with --pod-network-cidr
+const DefaultPodCIDR = "10.244.0.0/16"
+
+// manifestName is the name in the addons directory of a manifest given by path
+const manifestName = "cni.yaml"
+
+// addonManagerLabel marks the objects applied by the addon manager
+const addonManagerLabel = "addonmanager.kubernetes.io/mode"
+
+var bundled = map[string][]*assets.BinDataAsset{
+	Bridge: {
+		assets.NewBinDataAsset(
+			"deploy/cni/bridge/1-k8s.conf",
+			"/etc/cni/net.d",
+			"1-k8s.conf",
+			"0644"),
+	},
+	Flannel: {
+		assets.NewBinDataAsset(
+			"deploy/cni/flannel/kube-flannel.yaml",
+			constants.AddonsPath,
+			"kube-flannel.yaml",
+			"0640"),
+	},
+	Calico: {
+		assets.NewBinDataAsset(
+			"deploy/cni/calico/calico.yaml",
+			constants.AddonsPath,
+			"calico.yaml",
+			"0640"),
+	},
+}
+
+// Names returns the names of the bundled CNIs
+func Names() []string {
+	names := make([]string, 0, len(bundled))
+	for name := range bundled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsBundled returns whether cni is the name of a bundled CNI rather than the
+// path of a manifest
+func IsBundled(cni string) bool {
+	_, ok := bundled[cni]
+	return ok
+}
+
+// Validate checks that cni is either a bundled CNI or the path of a manifest
+// which the addon manager will apply, so its objects must be labeled with
+// addonmanager.kubernetes.io/mode.
+func Validate(cni string) error {
+	if IsBundled(cni) {
+		return nil
+	}
+	data, err := ioutil.ReadFile(cni)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.Errorf("%q is neither a bundled CNI (%s) nor a manifest", cni, strings.Join(Names(), ", "))
+		}
+		return errors.Wrapf(err, "reading CNI manifest %s", cni)
+	}
+	if !strings.Contains(string(data), addonManagerLabel) {
+		return errors.Errorf("the objects of CNI manifest %s must be labeled with %s: Reconcile to be applied", cni, addonManagerLabel)
+	}
+	return nil
+}
+
+// PodCIDR returns the pod network of the cluster, which defaults to
+// DefaultPodCIDR when a CNI is deployed.
+func PodCIDR(k8s config.KubernetesConfig) string {
+	if k8s.PodCIDR == "" && k8s.CNI != "" {
+		return DefaultPodCIDR
+	}
+	return k8s.PodCIDR
+}
+
+// Assets returns the files deploying the CNI of the cluster, if it has one.
+// The bundled manifests are rendered with the pod network of the cluster.
+func Assets(k8s config.KubernetesConfig) ([]assets.CopyableFile, error) {
+	if k8s.CNI == "" {
+		return nil, nil
+	}
+	manifests, ok := bundled[k8s.CNI]
+	if !ok {
+		f, err := assets.NewFileAsset(k8s.CNI, constants.AddonsPath, manifestName, "0640")
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading CNI manifest %s", k8s.CNI)
+		}
+		return []assets.CopyableFile{f}, nil
+	}
+	params := map[string]string{"podCIDR": PodCIDR(k8s)}
+	var files []assets.CopyableFile
+	for _, m := range manifests {
+		f, err := m.Evaluate(params)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rendering CNI %s", k8s.CNI)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cni")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	labeled := filepath.Join(dir, "labeled.yaml")
+	if err := ioutil.WriteFile(labeled, []byte("metadata:\n  labels:\n    addonmanager.kubernetes.io/mode: Reconcile\n"), 0644); err != nil {
+		t.Fatalf("Error writing manifest: %s", err)
+	}
+	unlabeled := filepath.Join(dir, "unlabeled.yaml")
+	if err := ioutil.WriteFile(unlabeled, []byte("metadata:\n  name: weave-net\n"), 0644); err != nil {
+		t.Fatalf("Error writing manifest: %s", err)
+	}
+
+	tests := []struct {
+		cni       string
+		shouldErr bool
+	}{
+		{cni: Bridge},
+		{cni: Flannel},
+		{cni: Calico},
+		{cni: labeled},
+		{cni: unlabeled, shouldErr: true},
+		{cni: "weave", shouldErr: true},
+		{cni: filepath.Join(dir, "missing.yaml"), shouldErr: true},
+	}
+	for _, test := range tests {
+		err := Validate(test.cni)
+		if err != nil && !test.shouldErr {
+			t.Errorf("Unexpected error validating %s: %s", test.cni, err)
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("Expected an error validating %s", test.cni)
+		}
+	}
+}
+
+func TestPodCIDR(t *testing.T) {
+	tests := []struct {
+		description string
+		k8s         config.KubernetesConfig
+		expected    string
+	}{
+		{
+			description: "no cni",
+			k8s:         config.KubernetesConfig{},
+			expected:    "",
+		},
+		{
+			description: "cni default",
+			k8s:         config.KubernetesConfig{CNI: Flannel},
+			expected:    DefaultPodCIDR,
+		},
+		{
+			description: "cni with pod network",
+			k8s:         config.KubernetesConfig{CNI: Calico, PodCIDR: "192.168.0.0/16"},
+			expected:    "192.168.0.0/16",
+		},
+	}
+	for _, test := range tests {
+		if actual := PodCIDR(test.k8s); actual != test.expected {
+			t.Errorf("%s: expected pod CIDR %q, got %q", test.description, test.expected, actual)
+		}
+	}
+}
+
+func TestManifestAssets(t *testing.T) {
+	files, err := Assets(config.KubernetesConfig{})
+	if err != nil || len(files) != 0 {
+		t.Errorf("Expected no assets without a cni, got %v, %v", files, err)
+	}
+
+	f, err := ioutil.TempFile("", "cni.yaml")
+	if err != nil {
+		t.Fatalf("Error creating manifest: %s", err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+
+	files, err = Assets(config.KubernetesConfig{CNI: f.Name()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Expected a single asset, got %d", len(files))
+	}
+	if files[0].GetTargetDir() != constants.AddonsPath || files[0].GetTargetName() != manifestName {
+		t.Errorf("Expected the manifest to be copied to %s/%s, got %s/%s", constants.AddonsPath, manifestName, files[0].GetTargetDir(), files[0].GetTargetName())
+	}
+}
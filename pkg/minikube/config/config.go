@@ -21,7 +21,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/viper"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -37,6 +39,7 @@ const (
 	MachineProfile                          = "profile"
 	ShowDriverDeprecationNotification       = "ShowDriverDeprecationNotification"
 	ShowBootstrapperDeprecationNotification = "ShowBootstrapperDeprecationNotification"
+	AddonParameters                         = "addon-parameters"
 )
 
 type MinikubeConfig map[string]interface{}
@@ -75,12 +78,71 @@ func ReadConfig() (MinikubeConfig, error) {
 	return m, nil
 }
 
+// GetAddonParameters returns the parameters stored in the config for the
+// given addon. Addons without stored parameters return an empty map.
+func GetAddonParameters(addonName string) (map[string]string, error) {
+	m, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return getAddonParameters(addonName, m), nil
+}
+
+func getAddonParameters(addonName string, config MinikubeConfig) map[string]string {
+	params := make(map[string]string)
+	addons, ok := config[AddonParameters].(map[string]interface{})
+	if !ok {
+		return params
+	}
+	values, ok := addons[addonName].(map[string]interface{})
+	if !ok {
+		return params
+	}
+	for k, v := range values {
+		params[k] = fmt.Sprintf("%v", v)
+	}
+	return params
+}
+
 func decode(r io.Reader) (MinikubeConfig, error) {
 	var data MinikubeConfig
 	err := json.NewDecoder(r).Decode(&data)
 	return data, err
 }
 
+// Load reads the cluster config saved for the given profile
+func Load(profile string) (Config, error) {
+	var cc Config
+
+	data, err := ioutil.ReadFile(constants.GetProfileFile(profile))
+	if err != nil {
+		return cc, err
+	}
+
+	if err := json.Unmarshal(data, &cc); err != nil {
+		return cc, err
+	}
+	return cc, nil
+}
+
+// ListProfiles returns the names of the profiles with a saved cluster config
+func ListProfiles() ([]string, error) {
+	dirs, err := ioutil.ReadDir(filepath.Join(constants.GetMinipath(), "profiles"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var profiles []string
+	for _, d := range dirs {
+		if _, err := os.Stat(constants.GetProfileFile(d.Name())); err == nil {
+			profiles = append(profiles, d.Name())
+		}
+	}
+	return profiles, nil
+}
+
 // GetMachineName gets the machine name for the VM
 func GetMachineName() string {
 	if viper.GetString(MachineProfile) == "" {
@@ -99,3 +99,33 @@ func TestGet(t *testing.T) {
 		}
 	}
 }
+
+func TestGetAddonParameters(t *testing.T) {
+	cfg := `{
+		"addon-parameters": {
+			"ingress": {
+				"controllerImage": "example.com/nginx-ingress-controller:1.0"
+			}
+		}
+	}`
+
+	config, err := decode(bytes.NewBufferString(cfg))
+	if err != nil {
+		t.Fatalf("Error decoding config : %v", err)
+	}
+
+	var testcases = []struct {
+		addon  string
+		params map[string]string
+	}{
+		{"ingress", map[string]string{"controllerImage": "example.com/nginx-ingress-controller:1.0"}},
+		{"registry", map[string]string{}},
+	}
+
+	for _, tt := range testcases {
+		params := getAddonParameters(tt.addon, config)
+		if !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("Expected %v for addon %s, got %v", tt.params, tt.addon, params)
+		}
+	}
+}
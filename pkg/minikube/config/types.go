@@ -30,24 +30,30 @@ type Config struct {
 
 // MachineConfig contains the parameters used to start a cluster.
 type MachineConfig struct {
-	MinikubeISO         string
-	Memory              int
-	CPUs                int
-	DiskSize            int
-	VMDriver            string
-	XhyveDiskDriver     string   // Only used by the xhyve driver
-	DockerEnv           []string // Each entry is formatted as KEY=VALUE.
-	InsecureRegistry    []string
-	RegistryMirror      []string
-	HostOnlyCIDR        string // Only used by the virtualbox driver
-	HypervVirtualSwitch string
-	KvmNetwork          string             // Only used by the KVM driver
-	Downloader          util.ISODownloader `json:"-"`
-	DockerOpt           []string           // Each entry is formatted as KEY=VALUE.
-	DisableDriverMounts bool               // Only used by virtualbox and xhyve
-	NFSShare            []string
-	NFSSharesRoot       string
-	UUID                string // Only used by hyperkit to restore the mac address
+	MinikubeISO           string
+	Memory                int
+	CPUs                  int
+	DiskSize              int
+	VMDriver              string
+	XhyveDiskDriver       string   // Only used by the xhyve driver
+	DockerEnv             []string // Each entry is formatted as KEY=VALUE.
+	InsecureRegistry      []string
+	RegistryMirror        []string
+	ServiceCIDR           string // Registries on the service network are trusted by docker
+	HostOnlyCIDR          string // Only used by the virtualbox driver
+	HypervVirtualSwitch   string
+	KvmNetwork            string             // Only used by the KVM driver
+	KvmPrivateNetworkCIDR string             // Only used by the kvm2 driver
+	ExtraDisks            int                // Only used by the kvm2 driver
+	KvmDiskFormat         string             // Only used by the kvm2 driver
+	NodeImage             string             // Only used by the docker driver
+	Downloader            util.ISODownloader `json:"-"`
+	DockerOpt             []string           // Each entry is formatted as KEY=VALUE.
+	DriverOptions         []string           // Each entry is formatted as KEY=VALUE. Only used by driver plugins
+	DisableDriverMounts   bool               // Only used by virtualbox and xhyve
+	NFSShare              []string
+	NFSSharesRoot         string
+	UUID                  string // Only used by hyperkit to restore the mac address
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -61,9 +67,19 @@ type KubernetesConfig struct {
 	DNSDomain         string
 	ContainerRuntime  string
 	NetworkPlugin     string
+	CNI               string
 	FeatureGates      string
 	ServiceCIDR       string
+	PodCIDR           string
 	ExtraOptions      util.ExtraOptionSlice
+	// KubeletConfigPatch and KubeProxyConfigPatch are YAML documents merged
+	// into the generated KubeletConfiguration and KubeProxyConfiguration
+	KubeletConfigPatch   string
+	KubeProxyConfigPatch string
+	// AuditPolicy and AdmissionConfig are the audit policy and the admission
+	// configuration file of the apiserver
+	AuditPolicy     string
+	AdmissionConfig string
 
 	ShouldLoadCachedImages bool
 }
@@ -56,6 +56,8 @@ var SupportedVMDrivers = [...]string{
 	"hyperv",
 	"hyperkit",
 	"kvm2",
+	"qemu",
+	"docker",
 	"none",
 }
 
@@ -106,8 +108,11 @@ const (
 	MinimumDiskSizeMB   = 2000
 	DefaultVMDriver     = "virtualbox"
 	DefaultStatusFormat = "minikube: {{.MinikubeStatus}}\n" +
-		"cluster: {{.ClusterStatus}}\n" + "kubectl: {{.KubeconfigStatus}}\n"
-	DefaultAddonListFormat     = "- {{.AddonName}}: {{.AddonStatus}}\n"
+		"cluster: {{.ClusterStatus}}\n" +
+		"{{range .Components}}  {{.Name}}: {{.State}}{{if .Error}} ({{.Error}}){{end}}\n{{end}}" +
+		"{{if .CNI}}cni: {{.CNI}}\n{{end}}" +
+		"kubectl: {{.KubeconfigStatus}}\n"
+	DefaultAddonListFormat     = "- {{.AddonName}}: {{.AddonStatus}}{{if .AddonHealth}} [{{.AddonHealth}}{{if .AddonHealthReason}}: {{.AddonHealthReason}}{{end}}]{{end}}{{if .AddonReason}} ({{.AddonReason}}){{end}}\n"
 	DefaultConfigViewFormat    = "- {{.ConfigKey}}: {{.ConfigValue}}\n"
 	DefaultCacheListFormat     = "{{.CacheImage}}\n"
 	GithubMinikubeReleasesURL  = "https://storage.googleapis.com/minikube/releases.json"
@@ -115,8 +120,17 @@ const (
 	DefaultWait                = 20
 	DefaultInterval            = 6
 	DefaultClusterBootstrapper = "kubeadm"
+	// DefaultKvmPrivateNetworkCIDR is the private network created by the kvm2 driver
+	DefaultKvmPrivateNetworkCIDR = "192.168.39.0/24"
+	// KvmDockerMachinesNetworkCIDR is the private network created by the
+	// docker-machine-kvm driver, which can't be configured
+	KvmDockerMachinesNetworkCIDR = "192.168.42.0/24"
 )
 
+// DefaultNodeImage is the image the docker driver runs nodes from. It is
+// versioned along with the ISO, since it provides the same node environment.
+var DefaultNodeImage = "gcr.io/k8s-minikube/node-image:" + minikubeVersion.GetIsoVersion()
+
 var DefaultIsoUrl = fmt.Sprintf("https://storage.googleapis.com/%s/minikube-%s.iso", minikubeVersion.GetIsoPath(), minikubeVersion.GetIsoVersion())
 var DefaultIsoShaUrl = DefaultIsoUrl + ShaSuffix
 
@@ -151,6 +165,16 @@ const (
 	KubeletServiceFile     = "/lib/systemd/system/kubelet.service"
 	KubeletSystemdConfFile = "/etc/systemd/system/kubelet.service.d/10-kubeadm.conf"
 	KubeadmConfigFile      = "/var/lib/kubeadm.yaml"
+	// KubeletConfigFile is the KubeletConfiguration of kubelets which read one,
+	// away from /var/lib/kubelet/config.yaml which kubeadm init overwrites
+	KubeletConfigFile = "/etc/kubernetes/kubelet-config.yaml"
+	// AuditPolicyFile and AdmissionConfigFile are mounted into the apiserver
+	// static pod, as is the directory of AuditLogFile
+	AuditPolicyFile     = "/etc/kubernetes/audit/policy.yaml"
+	AuditLogFile        = "/var/log/kubernetes/audit/audit.log"
+	AdmissionConfigFile = "/etc/kubernetes/admission/config.yaml"
+	// APIServerManifest is the apiserver static pod manifest written by kubeadm
+	APIServerManifest = "/etc/kubernetes/manifests/kube-apiserver.yaml"
 )
 
 var Preflights = []string{
@@ -0,0 +1,72 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/docker"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+func init() {
+	registry.Register(registry.DriverDef{
+		Name:          "docker",
+		Builtin:       true,
+		ConfigCreator: createDockerHost,
+		Status:        status,
+		// Never picked automatically, since the default node image isn't
+		// published yet and has to be built with make node-image
+		Priority: 0,
+		DriverCreator: func() drivers.Driver {
+			return docker.NewDriver("", "")
+		},
+	})
+}
+
+func createDockerHost(config cfg.MachineConfig) interface{} {
+	d := docker.NewDriver(cfg.GetMachineName(), constants.GetMinipath())
+	d.Image = config.NodeImage
+	if d.Image == "" {
+		d.Image = constants.DefaultNodeImage
+	}
+	d.Memory = config.Memory
+	d.CPU = config.CPUs
+	return d
+}
+
+func status() registry.State {
+	path, err := exec.LookPath("docker")
+	if err != nil {
+		return registry.State{Error: err, Fix: "Install docker, see https://docs.docker.com/install/"}
+	}
+	out, err := exec.Command(path, "version", "--format", "{{.Server.Version}}").CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		fix := "Start the docker daemon"
+		if strings.Contains(msg, "permission denied") {
+			fix = "Add your user to the docker group with `sudo usermod -a -G docker $(whoami)`, then log in again"
+		}
+		return registry.State{Installed: true, Error: errors.Wrapf(err, "docker version: %s", msg), Fix: fix}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
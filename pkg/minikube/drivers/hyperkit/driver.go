@@ -19,7 +19,12 @@ limitations under the License.
 package hyperkit
 
 import (
+	"os"
+	"os/exec"
+	"syscall"
+
 	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
 	"github.com/pborman/uuid"
 	"k8s.io/minikube/pkg/drivers/hyperkit"
 	cfg "k8s.io/minikube/pkg/minikube/config"
@@ -32,6 +37,8 @@ func init() {
 		Name:          "hyperkit",
 		Builtin:       false,
 		ConfigCreator: createHyperkitHost,
+		Status:        status,
+		Priority:      80,
 	})
 }
 
@@ -52,3 +59,23 @@ func createHyperkitHost(config cfg.MachineConfig) interface{} {
 		Cmdline:        "loglevel=3 user=docker console=ttyS0 console=tty0 noembed nomodeset norestore waitusb=10 systemd.legacy_systemd_cgroup_controller=yes base host=" + cfg.GetMachineName(),
 	}
 }
+
+func status() registry.State {
+	path, err := exec.LookPath("docker-machine-driver-hyperkit")
+	if err != nil {
+		return registry.State{Error: err, Fix: "Install docker-machine-driver-hyperkit, see https://github.com/kubernetes/minikube/blob/master/docs/drivers.md#hyperkit-driver"}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return registry.State{Installed: true, Error: err, Fix: "Reinstall docker-machine-driver-hyperkit"}
+	}
+	// hyperkit needs root to set up the VM network
+	if sys, ok := info.Sys().(*syscall.Stat_t); !ok || sys.Uid != 0 || info.Mode()&os.ModeSetuid == 0 {
+		return registry.State{
+			Installed: true,
+			Error:     errors.Errorf("%s is not owned by root with the setuid bit set", path),
+			Fix:       "Run `sudo chown root:wheel " + path + " && sudo chmod u+s " + path + "`",
+		}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
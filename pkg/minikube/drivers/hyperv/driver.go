@@ -19,8 +19,12 @@ limitations under the License.
 package hyperv
 
 import (
+	"os/exec"
+	"strings"
+
 	"github.com/docker/machine/drivers/hyperv"
 	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/registry"
@@ -31,6 +35,8 @@ func init() {
 		Name:          "hyperv",
 		Builtin:       true,
 		ConfigCreator: createHypervHost,
+		Status:        status,
+		Priority:      80,
 		DriverCreator: func() drivers.Driver {
 			return hyperv.NewDriver("", "")
 		},
@@ -49,3 +55,26 @@ func createHypervHost(config cfg.MachineConfig) interface{} {
 
 	return d
 }
+
+func status() registry.State {
+	path, err := exec.LookPath("powershell")
+	if err != nil {
+		return registry.State{Error: err, Fix: "Hyper-V requires PowerShell"}
+	}
+	out, err := exec.Command(path, "-NoProfile", "-NonInteractive", "@(Get-Command Get-VM).ModuleName").CombinedOutput()
+	if err != nil || strings.TrimSpace(string(out)) != "Hyper-V" {
+		return registry.State{
+			Error: errors.Errorf("the Hyper-V PowerShell module is not available: %s", strings.TrimSpace(string(out))),
+			Fix:   "Enable Hyper-V with `Enable-WindowsOptionalFeature -Online -FeatureName Microsoft-Hyper-V -All` in an elevated PowerShell",
+		}
+	}
+	out, err = exec.Command(path, "-NoProfile", "-NonInteractive", "Get-VM").CombinedOutput()
+	if err != nil {
+		return registry.State{
+			Installed: true,
+			Error:     errors.Wrapf(err, "Get-VM: %s", strings.TrimSpace(string(out))),
+			Fix:       "Run minikube from an elevated shell, or add your user to the Hyper-V Administrators group",
+		}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/docker/machine/libmachine/drivers"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/drivers/kvm2"
 	"k8s.io/minikube/pkg/minikube/registry"
 )
 
@@ -33,6 +34,11 @@ func init() {
 		Name:          "kvm",
 		Builtin:       false,
 		ConfigCreator: createKVMHost,
+		Status: func() registry.State {
+			return kvm2.ProbeKVM("docker-machine-driver-kvm", "https://github.com/kubernetes/minikube/blob/master/docs/drivers.md#kvm-driver")
+		},
+		// The kvm driver is replaced by kvm2
+		Priority: 20,
 	})
 }
 
@@ -33,6 +33,8 @@ func init() {
 		Name:          "kvm2",
 		Builtin:       false,
 		ConfigCreator: createKVM2Host,
+		Status:        status,
+		Priority:      80,
 	})
 }
 
@@ -41,34 +43,50 @@ func init() {
 type kvmDriver struct {
 	*drivers.BaseDriver
 
-	Memory         int
-	DiskSize       int
-	CPU            int
-	Network        string
-	PrivateNetwork string
-	ISO            string
-	Boot2DockerURL string
-	DiskPath       string
-	CacheMode      string
-	IOMode         string
+	Memory             int
+	DiskSize           int
+	CPU                int
+	Network            string
+	PrivateNetwork     string
+	PrivateNetworkCIDR string
+	ISO                string
+	Boot2DockerURL     string
+	DiskPath           string
+	DiskFormat         string
+	ExtraDisks         int
+	CacheMode          string
+	IOMode             string
 }
 
 func createKVM2Host(config cfg.MachineConfig) interface{} {
+	machineName := cfg.GetMachineName()
 	return &kvmDriver{
 		BaseDriver: &drivers.BaseDriver{
-			MachineName: cfg.GetMachineName(),
+			MachineName: machineName,
 			StorePath:   constants.GetMinipath(),
 			SSHUser:     "docker",
 		},
-		Memory:         config.Memory,
-		CPU:            config.CPUs,
-		Network:        config.KvmNetwork,
-		PrivateNetwork: "minikube-net",
-		Boot2DockerURL: config.Downloader.GetISOFileURI(config.MinikubeISO),
-		DiskSize:       config.DiskSize,
-		DiskPath:       filepath.Join(constants.GetMinipath(), "machines", cfg.GetMachineName(), fmt.Sprintf("%s.rawdisk", cfg.GetMachineName())),
-		ISO:            filepath.Join(constants.GetMinipath(), "machines", cfg.GetMachineName(), "boot2docker.iso"),
-		CacheMode:      "default",
-		IOMode:         "threads",
+		Memory:  config.Memory,
+		CPU:     config.CPUs,
+		Network: config.KvmNetwork,
+		// Each profile gets its own network, see privateNetworkName in pkg/drivers/kvm
+		PrivateNetwork:     fmt.Sprintf("%s-net", machineName),
+		PrivateNetworkCIDR: config.KvmPrivateNetworkCIDR,
+		Boot2DockerURL:     config.Downloader.GetISOFileURI(config.MinikubeISO),
+		DiskSize:           config.DiskSize,
+		DiskPath:           diskPath(machineName, config.KvmDiskFormat),
+		DiskFormat:         config.KvmDiskFormat,
+		ExtraDisks:         config.ExtraDisks,
+		ISO:                filepath.Join(constants.GetMinipath(), "machines", machineName, "boot2docker.iso"),
+		CacheMode:          "default",
+		IOMode:             "threads",
 	}
 }
+
+func diskPath(machineName, format string) string {
+	ext := "rawdisk"
+	if format == "qcow2" {
+		ext = "qcow2"
+	}
+	return filepath.Join(constants.GetMinipath(), "machines", machineName, fmt.Sprintf("%s.%s", machineName, ext))
+}
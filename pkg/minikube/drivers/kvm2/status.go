@@ -0,0 +1,107 @@
+// +build linux
+
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvm2
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+const (
+	libvirtSocket = "/var/run/libvirt/libvirt-sock"
+	cpuInfo       = "/proc/cpuinfo"
+	kvmDevice     = "/dev/kvm"
+)
+
+func status() registry.State {
+	return ProbeKVM("docker-machine-driver-kvm2", "https://github.com/kubernetes/minikube/blob/master/docs/drivers.md#kvm2-driver")
+}
+
+// ProbeKVM checks that a libvirt based driver plugin can be used: the plugin
+// is installed, the CPU supports virtualization, KVM is loaded and the
+// libvirt daemon is reachable.
+func ProbeKVM(plugin, docs string) registry.State {
+	if _, err := exec.LookPath(plugin); err != nil {
+		return registry.State{Error: err, Fix: "Install " + plugin + ", see " + docs}
+	}
+
+	if f, err := os.Open(cpuInfo); err == nil {
+		virt := hasVirtFlags(f)
+		f.Close()
+		if !virt {
+			return registry.State{
+				Installed: true,
+				Error:     errors.New("the CPU doesn't support virtualization"),
+				Fix:       "Enable VT-x or AMD-V in the BIOS, or nested virtualization if this host is a VM",
+			}
+		}
+	}
+
+	if _, err := os.Stat(kvmDevice); err != nil {
+		return registry.State{
+			Installed: true,
+			Error:     errors.Wrap(err, "KVM is not available"),
+			Fix:       "Load the KVM kernel module with `sudo modprobe kvm_intel` or `sudo modprobe kvm_amd`",
+		}
+	}
+
+	if _, err := os.Stat(libvirtSocket); err != nil {
+		return registry.State{
+			Installed: true,
+			Error:     errors.Wrap(err, "libvirtd is not running"),
+			Fix:       "Install libvirt and start it with `sudo systemctl start libvirtd`, see " + docs,
+		}
+	}
+	conn, err := net.Dial("unix", libvirtSocket)
+	if err != nil {
+		return registry.State{
+			Installed: true,
+			Error:     errors.Wrap(err, "connecting to libvirtd"),
+			Fix:       "Add your user to the libvirt group with `sudo usermod -a -G libvirt $(whoami)`, then log in again or run `newgrp libvirt`",
+		}
+	}
+	conn.Close()
+
+	return registry.State{Installed: true, Healthy: true}
+}
+
+// hasVirtFlags returns whether the CPUs listed in the /proc/cpuinfo format
+// support Intel VT-x or AMD-V.
+func hasVirtFlags(r io.Reader) bool {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "flags") {
+			continue
+		}
+		for _, flag := range strings.Fields(line) {
+			if flag == "vmx" || flag == "svm" {
+				return true
+			}
+		}
+	}
+	return false
+}
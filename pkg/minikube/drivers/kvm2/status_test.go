@@ -0,0 +1,42 @@
+// +build linux
+
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvm2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHasVirtFlags(t *testing.T) {
+	var tests = []struct {
+		cpuinfo  string
+		expected bool
+	}{
+		{"processor\t: 0\nflags\t\t: fpu vme de pse tsc msr vmx smx est\n", true},
+		{"processor\t: 0\nflags\t\t: fpu svm extapic\n", true},
+		{"processor\t: 0\nflags\t\t: fpu vme de pse tsc msr hypervisor\n", false},
+		// vmx outside of the flags line doesn't count
+		{"model name\t: vmx\nflags\t\t: fpu\n", false},
+	}
+	for _, test := range tests {
+		if got := hasVirtFlags(strings.NewReader(test.cpuinfo)); got != test.expected {
+			t.Errorf("Expected %t for %q, got %t", test.expected, test.cpuinfo, got)
+		}
+	}
+}
@@ -19,7 +19,11 @@ limitations under the License.
 package none
 
 import (
+	"os"
+	"os/exec"
+
 	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/drivers/none"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -31,6 +35,9 @@ func init() {
 		Name:          "none",
 		Builtin:       true,
 		ConfigCreator: createNoneHost,
+		Status:        status,
+		// none runs on the host itself, so it is never picked automatically
+		Priority: 0,
 		DriverCreator: func() drivers.Driver {
 			return none.NewDriver("", "")
 		},
@@ -45,3 +52,13 @@ func createNoneHost(config cfg.MachineConfig) interface{} {
 		},
 	}
 }
+
+func status() registry.State {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return registry.State{Error: err, Fix: "Install docker, which the none driver runs Kubernetes on"}
+	}
+	if os.Geteuid() != 0 {
+		return registry.State{Installed: true, Error: errors.New("the none driver must be run as root"), Fix: "Run minikube with `sudo -E`"}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
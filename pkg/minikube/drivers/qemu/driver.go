@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/qemu"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+func init() {
+	registry.Register(registry.DriverDef{
+		Name:          "qemu",
+		Builtin:       true,
+		ConfigCreator: createQemuHost,
+		Status:        status,
+		Priority:      40,
+		DriverCreator: func() drivers.Driver {
+			return qemu.NewDriver("", "")
+		},
+	})
+}
+
+func createQemuHost(config cfg.MachineConfig) interface{} {
+	d := qemu.NewDriver(cfg.GetMachineName(), constants.GetMinipath())
+	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
+	d.Memory = config.Memory
+	d.CPU = config.CPUs
+	d.DiskSize = config.DiskSize
+	return d
+}
+
+func status() registry.State {
+	if _, err := exec.LookPath(qemu.DefaultBinary); err != nil {
+		return registry.State{Error: err, Fix: "Install qemu, see https://www.qemu.org/download/"}
+	}
+	if _, err := os.Stat("/dev/kvm"); err != nil {
+		// qemu still works without KVM, but emulates the VM slowly
+		return registry.State{
+			Installed: true,
+			Healthy:   true,
+			Error:     errors.Wrap(err, "KVM is not available"),
+			Fix:       "Load the KVM kernel module with `sudo modprobe kvm_intel` or `sudo modprobe kvm_amd`",
+		}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
@@ -17,8 +17,14 @@ limitations under the License.
 package virtualbox
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
 	"github.com/docker/machine/drivers/virtualbox"
 	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/registry"
@@ -31,6 +37,8 @@ func init() {
 		Name:          "virtualbox",
 		Builtin:       true,
 		ConfigCreator: createVirtualboxHost,
+		Status:        status,
+		Priority:      60,
 		DriverCreator: func() drivers.Driver {
 			return virtualbox.NewDriver("", "")
 		},
@@ -51,3 +59,30 @@ func createVirtualboxHost(config cfg.MachineConfig) interface{} {
 
 	return d
 }
+
+func status() registry.State {
+	path, err := vboxManage()
+	if err != nil {
+		return registry.State{Error: err, Fix: "Install VirtualBox from https://www.virtualbox.org/wiki/Downloads"}
+	}
+	out, err := exec.Command(path, "--version").CombinedOutput()
+	if err != nil {
+		return registry.State{Installed: true, Error: errors.Wrapf(err, "%s --version: %s", path, out), Fix: "Reinstall VirtualBox"}
+	}
+	// VBoxManage warns instead of failing when the kernel driver is missing
+	if strings.Contains(string(out), "WARNING") {
+		return registry.State{Installed: true, Error: errors.New(strings.TrimSpace(string(out))), Fix: "Load the VirtualBox kernel driver, e.g. with `sudo /sbin/vboxconfig`"}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
+
+func vboxManage() (string, error) {
+	for _, env := range []string{"VBOX_INSTALL_PATH", "VBOX_MSI_INSTALL_PATH"} {
+		if dir := os.Getenv(env); dir != "" {
+			if path, err := exec.LookPath(filepath.Join(dir, "VBoxManage")); err == nil {
+				return path, nil
+			}
+		}
+	}
+	return exec.LookPath("VBoxManage")
+}
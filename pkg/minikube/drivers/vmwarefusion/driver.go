@@ -19,6 +19,8 @@ limitations under the License.
 package vmwarefusion
 
 import (
+	"os"
+
 	"github.com/docker/machine/drivers/vmwarefusion"
 	"github.com/docker/machine/libmachine/drivers"
 	cfg "k8s.io/minikube/pkg/minikube/config"
@@ -31,6 +33,8 @@ func init() {
 		Name:          "vmwarefusion",
 		Builtin:       true,
 		ConfigCreator: createVMwareFusionHost,
+		Status:        status,
+		Priority:      50,
 		DriverCreator: func() drivers.Driver {
 			return vmwarefusion.NewDriver("", "")
 		},
@@ -49,3 +53,12 @@ func createVMwareFusionHost(config cfg.MachineConfig) interface{} {
 	d.ISO = d.ResolveStorePath("boot2docker.iso")
 	return d
 }
+
+const vmrun = "/Applications/VMware Fusion.app/Contents/Library/vmrun"
+
+func status() registry.State {
+	if _, err := os.Stat(vmrun); err != nil {
+		return registry.State{Error: err, Fix: "Install VMware Fusion from https://www.vmware.com/products/fusion.html"}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
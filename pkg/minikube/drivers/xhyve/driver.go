@@ -21,6 +21,7 @@ package xhyve
 import (
 	"fmt"
 	"os"
+	"os/exec"
 
 	"github.com/docker/machine/libmachine/drivers"
 	cfg "k8s.io/minikube/pkg/minikube/config"
@@ -38,6 +39,9 @@ func init() {
 		Name:          "xhyve",
 		Builtin:       false,
 		ConfigCreator: createXhyveHost,
+		Status:        status,
+		// xhyve is deprecated in favor of hyperkit
+		Priority: 10,
 		DriverCreator: func() drivers.Driver {
 			fmt.Fprintln(os.Stderr, errMsg)
 			os.Exit(1)
@@ -84,3 +88,10 @@ func createXhyveHost(config cfg.MachineConfig) interface{} {
 		RawDisk:        config.XhyveDiskDriver == "virtio-blk",
 	}
 }
+
+func status() registry.State {
+	if _, err := exec.LookPath("docker-machine-driver-xhyve"); err != nil {
+		return registry.State{Error: err, Fix: "Install docker-machine-driver-xhyve, see https://github.com/kubernetes/minikube/blob/master/docs/drivers.md#xhyve-driver"}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/machine/libmachine/drivers"
+	rpcdriver "github.com/docker/machine/libmachine/drivers/rpc"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// PluginPrefix is the file name prefix of docker-machine driver plugins
+const PluginPrefix = "docker-machine-driver-"
+
+// PluginDirs returns the directories searched for driver plugins, in order:
+// $MINIKUBE_HOME/bin, then the directories on PATH.
+func PluginDirs() []string {
+	return append([]string{constants.MakeMiniPath("bin")}, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+var loadPlugins sync.Once
+
+// LoadPlugins registers the driver plugins found in PluginDirs, the first time
+// it is called. Plugins are only searched for when a driver isn't registered
+// or the drivers are probed, so other commands don't read PATH.
+func LoadPlugins() {
+	loadPlugins.Do(func() {
+		DiscoverPlugins(PluginDirs())
+	})
+}
+
+// DiscoverPlugins registers the driver plugins found in dirs whose name isn't
+// registered yet, and returns them. When several dirs contain the same
+// plugin, the first one is used.
+func DiscoverPlugins(dirs []string) []DriverDef {
+	var found []DriverDef
+	var pluginDirs []string
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			name, ok := pluginName(f)
+			if !ok {
+				continue
+			}
+			if _, err := registry.Driver(name); err == nil {
+				continue
+			}
+			def := DriverDef{
+				Name:          name,
+				Builtin:       false,
+				ConfigCreator: createPluginHost,
+				PluginPath:    filepath.Join(dir, f.Name()),
+			}
+			if err := Register(def); err != nil {
+				glog.Warningf("Unable to register driver plugin %s: %s", def.PluginPath, err)
+				continue
+			}
+			glog.Infof("Found driver plugin %s", def.PluginPath)
+			found = append(found, def)
+			if len(pluginDirs) == 0 || pluginDirs[len(pluginDirs)-1] != dir {
+				pluginDirs = append(pluginDirs, dir)
+			}
+		}
+	}
+	if len(pluginDirs) > 0 {
+		if err := os.Setenv("PATH", prependPath(os.Getenv("PATH"), pluginDirs)); err != nil {
+			glog.Warningf("Unable to add %s to PATH: %s", strings.Join(pluginDirs, ", "), err)
+		}
+	}
+	return found
+}
+
+// pluginName returns the driver name of a plugin executable
+func pluginName(f os.FileInfo) (string, bool) {
+	name := f.Name()
+	if runtime.GOOS == "windows" {
+		if !strings.HasSuffix(name, ".exe") {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ".exe")
+	} else if f.Mode()&0111 == 0 {
+		return "", false
+	}
+	if f.IsDir() || !strings.HasPrefix(name, PluginPrefix) || name == PluginPrefix {
+		return "", false
+	}
+	return strings.TrimPrefix(name, PluginPrefix), true
+}
+
+// prependPath moves the dirs of the discovered plugins to the front of path,
+// in order, since libmachine runs the first plugin of a name found on PATH,
+// which must be the one that was registered.
+func prependPath(path string, dirs []string) string {
+	entries := append([]string{}, dirs...)
+	for _, d := range filepath.SplitList(path) {
+		moved := false
+		for _, dir := range dirs {
+			if d == dir {
+				moved = true
+			}
+		}
+		if !moved {
+			entries = append(entries, d)
+		}
+	}
+	return strings.Join(entries, string(os.PathListSeparator))
+}
+
+// createPluginHost creates the config of a plugin driver. Plugins are
+// configured through their create flags rather than their raw config, whose
+// format is unknown, so only the base driver config is set here.
+func createPluginHost(_ config.MachineConfig) interface{} {
+	return &drivers.BaseDriver{
+		MachineName: config.GetMachineName(),
+		StorePath:   constants.GetMinipath(),
+		SSHUser:     "docker",
+	}
+}
+
+// PluginFlags returns the values of a plugin's create flags: their defaults,
+// overridden by the key=value options the user passed.
+func PluginFlags(flags []mcnflag.Flag, opts []string) (*rpcdriver.RPCFlags, error) {
+	values := map[string]interface{}{}
+	byName := map[string]mcnflag.Flag{}
+	set := map[string]bool{}
+	for _, f := range flags {
+		values[f.String()] = f.Default()
+		byName[f.String()] = f
+		// Bool flags have no default, but nil values can't be sent to plugins
+		if values[f.String()] == nil {
+			values[f.String()] = false
+		}
+	}
+
+	for _, opt := range opts {
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("driver option %q is not formatted as key=value", opt)
+		}
+		key, val := kv[0], kv[1]
+		f, ok := byName[key]
+		if !ok {
+			return nil, errors.Errorf("unknown driver option %s, valid options are: %s", key, strings.Join(flagNames(flags), ", "))
+		}
+		switch f.(type) {
+		case mcnflag.IntFlag, *mcnflag.IntFlag:
+			i, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "driver option %s must be an integer", key)
+			}
+			values[key] = i
+		case mcnflag.BoolFlag, *mcnflag.BoolFlag:
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "driver option %s must be a boolean", key)
+			}
+			values[key] = b
+		case mcnflag.StringSliceFlag, *mcnflag.StringSliceFlag:
+			// Repeated options accumulate, like repeated command line flags
+			if s, ok := values[key].([]string); ok && set[key] {
+				values[key] = append(s, val)
+			} else {
+				values[key] = []string{val}
+			}
+		default:
+			values[key] = val
+		}
+		set[key] = true
+	}
+	return &rpcdriver.RPCFlags{Values: values}, nil
+}
+
+func flagNames(flags []mcnflag.Flag) []string {
+	var names []string
+	for _, f := range flags {
+		names = append(names, f.String())
+	}
+	sort.Strings(names)
+	return names
+}
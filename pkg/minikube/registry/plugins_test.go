@@ -0,0 +1,159 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/machine/libmachine/mcnflag"
+)
+
+func TestDiscoverPlugins(t *testing.T) {
+	first, err := ioutil.TempDir("", "plugins")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(first)
+	second, err := ioutil.TempDir("", "plugins")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(second)
+
+	for path, mode := range map[string]os.FileMode{
+		filepath.Join(first, "docker-machine-driver-testplugin"):     0755,
+		filepath.Join(first, "docker-machine-driver-notexecutable"):  0644,
+		filepath.Join(first, "other-binary"):                         0755,
+		filepath.Join(second, "docker-machine-driver-testplugin"):    0755,
+		filepath.Join(second, "docker-machine-driver-otherplugin"):   0755,
+		filepath.Join(second, "docker-machine-driver-registeredfoo"): 0755,
+	} {
+		if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatalf("Error writing %s: %s", path, err)
+		}
+	}
+	if err := Register(DriverDef{Name: "registeredfoo", Builtin: true}); err != nil {
+		t.Fatalf("Error registering driver: %s", err)
+	}
+
+	found := DiscoverPlugins([]string{first, second, filepath.Join(first, "missing")})
+	paths := map[string]string{}
+	for _, def := range found {
+		paths[def.Name] = def.PluginPath
+	}
+	expected := map[string]string{
+		"testplugin":  filepath.Join(first, "docker-machine-driver-testplugin"),
+		"otherplugin": filepath.Join(second, "docker-machine-driver-otherplugin"),
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected plugins %v, got %v", expected, paths)
+	}
+
+	def, err := Driver("testplugin")
+	if err != nil {
+		t.Fatalf("Expected testplugin to be registered: %s", err)
+	}
+	if def.Builtin || def.ConfigCreator == nil {
+		t.Errorf("Expected an RPC driver with a config creator, got %s", def)
+	}
+}
+
+func TestPluginFlags(t *testing.T) {
+	flags := []mcnflag.Flag{
+		mcnflag.StringFlag{Name: "foo-url", Value: "http://default"},
+		&mcnflag.IntFlag{Name: "foo-cpus", Value: 1},
+		mcnflag.BoolFlag{Name: "foo-nested"},
+		mcnflag.StringSliceFlag{Name: "foo-tags", Value: []string{"default"}},
+	}
+
+	f, err := PluginFlags(flags, nil)
+	if err != nil {
+		t.Fatalf("Error getting default flags: %s", err)
+	}
+	expected := map[string]interface{}{
+		"foo-url":    "http://default",
+		"foo-cpus":   1,
+		"foo-nested": false,
+		"foo-tags":   []string{"default"},
+	}
+	if !reflect.DeepEqual(f.Values, expected) {
+		t.Errorf("Expected defaults %v, got %v", expected, f.Values)
+	}
+
+	f, err = PluginFlags(flags, []string{"foo-url=http://a=b", "foo-cpus=4", "foo-nested=true", "foo-tags=a", "foo-tags=b"})
+	if err != nil {
+		t.Fatalf("Error getting flags: %s", err)
+	}
+	expected = map[string]interface{}{
+		"foo-url":    "http://a=b",
+		"foo-cpus":   4,
+		"foo-nested": true,
+		"foo-tags":   []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(f.Values, expected) {
+		t.Errorf("Expected flags %v, got %v", expected, f.Values)
+	}
+
+	for _, opts := range [][]string{
+		{"foo-cpus"},
+		{"foo-cpus=many"},
+		{"foo-nested=maybe"},
+		{"foo-unknown=1"},
+	} {
+		if _, err := PluginFlags(flags, opts); err == nil {
+			t.Errorf("Expected an error for options %v", opts)
+		}
+	}
+}
+
+func TestPrependPath(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	path := strings.Join([]string{"/usr/bin", "/home/user/.minikube/bin", "/opt/bin"}, sep)
+	expected := strings.Join([]string{"/home/user/.minikube/bin", "/opt/bin", "/usr/bin"}, sep)
+	if actual := prependPath(path, []string{"/home/user/.minikube/bin", "/opt/bin"}); actual != expected {
+		t.Errorf("Expected PATH %s, got %s", expected, actual)
+	}
+}
+
+func TestDriverLoadsPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugins")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "docker-machine-driver-lazyplugin")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Error writing %s: %s", path, err)
+	}
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir)
+	loadPlugins = sync.Once{}
+
+	def, err := Driver("lazyplugin")
+	if err != nil {
+		t.Fatalf("Expected lazyplugin to be discovered: %s", err)
+	}
+	if def.PluginPath != path {
+		t.Errorf("Expected plugin path %s, got %s", path, def.PluginPath)
+	}
+}
@@ -53,6 +53,22 @@ type ConfigFactory func(config.MachineConfig) interface{}
 // DriverFactory is a function that load a byte stream and create a driver
 type DriverFactory func() drivers.Driver
 
+// StatusChecker is a function that probes whether a driver can be used on
+// the host
+type StatusChecker func() State
+
+// State is the result of a driver health probe
+type State struct {
+	// Installed is whether the driver's binaries are present
+	Installed bool
+	// Healthy is whether the driver can be used as is
+	Healthy bool
+	// Error describes why the driver can't be used, or what may go wrong
+	Error error
+	// Fix is a hint on how to make the driver usable
+	Fix string
+}
+
 // DriverDef defines a machine driver metadata. It tells minikube how to initialize
 // and load drivers.
 type DriverDef struct {
@@ -68,9 +84,24 @@ type DriverDef struct {
 
 	// DriverCreator is the factory method that creates a machine driver instance.
 	DriverCreator DriverFactory
+
+	// Status probes whether the driver can be used on the host. Drivers
+	// without a probe are assumed to be usable.
+	Status StatusChecker
+
+	// Priority orders healthy drivers when one is picked automatically,
+	// highest first. Drivers with a priority of 0 are never picked.
+	Priority int
+
+	// PluginPath is the path of a discovered docker-machine driver plugin.
+	// Plugins are configured through their create flags, see PluginFlags.
+	PluginPath string
 }
 
 func (d DriverDef) String() string {
+	if d.PluginPath != "" {
+		return fmt.Sprintf("{name: %s, builtin: %t, plugin: %s}", d.Name, d.Builtin, d.PluginPath)
+	}
 	return fmt.Sprintf("{name: %s, builtin: %t}", d.Name, d.Builtin)
 }
 
@@ -97,8 +128,15 @@ func Register(driver DriverDef) error {
 	return registry.Register(driver)
 }
 
+// Driver returns the driver of a given name, discovering the driver plugins
+// if it isn't registered.
 func Driver(name string) (DriverDef, error) {
-	return registry.Driver(name)
+	def, err := registry.Driver(name)
+	if err == ErrDriverNotFound {
+		LoadPlugins()
+		return registry.Driver(name)
+	}
+	return def, err
 }
 
 func (r *driverRegistry) Register(def DriverDef) error {
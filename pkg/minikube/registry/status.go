@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// DriverState is a driver along with the result of its health probe
+type DriverState struct {
+	Def   DriverDef
+	State State
+}
+
+// Probe returns the health of a driver. Drivers without a probe are assumed
+// to be installed and healthy.
+func (d DriverDef) Probe() State {
+	if d.Status == nil {
+		return State{Installed: true, Healthy: true}
+	}
+	return d.Status()
+}
+
+// ProbeDrivers probes every registered driver and driver plugin, returning
+// them by priority, highest first, then by name.
+func ProbeDrivers() []DriverState {
+	LoadPlugins()
+	return probe(ListDrivers())
+}
+
+func probe(defs []DriverDef) []DriverState {
+	var states []DriverState
+	for _, def := range defs {
+		states = append(states, DriverState{Def: def, State: def.Probe()})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Def.Priority != states[j].Def.Priority {
+			return states[i].Def.Priority > states[j].Def.Priority
+		}
+		return states[i].Def.Name < states[j].Def.Name
+	})
+	return states
+}
+
+// Choose returns the healthy driver with the highest priority among states,
+// which must be ordered as returned by ProbeDrivers.
+func Choose(states []DriverState) (DriverDef, error) {
+	for _, s := range states {
+		if s.Def.Priority > 0 && s.State.Healthy {
+			return s.Def, nil
+		}
+	}
+	return DriverDef{}, errors.New("no usable driver was found, run `minikube drivers` to see how to set one up")
+}
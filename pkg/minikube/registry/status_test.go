@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func healthy() State {
+	return State{Installed: true, Healthy: true}
+}
+
+func broken() State {
+	return State{Installed: true, Error: errors.New("broken")}
+}
+
+func TestProbe(t *testing.T) {
+	defs := []DriverDef{
+		{Name: "b", Priority: 50, Status: healthy},
+		{Name: "none", Priority: 0},
+		{Name: "a", Priority: 50, Status: broken},
+		{Name: "c", Priority: 80, Status: broken},
+	}
+	states := probe(defs)
+
+	var names []string
+	for _, s := range states {
+		names = append(names, s.Def.Name)
+	}
+	expected := []string{"c", "a", "b", "none"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("Expected drivers ordered as %v, got %v", expected, names)
+		}
+	}
+	if !states[3].State.Healthy {
+		t.Errorf("Expected a driver without probe to be healthy")
+	}
+
+	def, err := Choose(states)
+	if err != nil {
+		t.Fatalf("Unexpected error choosing a driver: %s", err)
+	}
+	if def.Name != "b" {
+		t.Errorf("Expected driver b to be chosen, got %s", def.Name)
+	}
+}
+
+func TestChooseNoUsableDriver(t *testing.T) {
+	states := probe([]DriverDef{
+		{Name: "none", Priority: 0, Status: healthy},
+		{Name: "a", Priority: 50, Status: broken},
+	})
+	if def, err := Choose(states); err == nil {
+		t.Errorf("Expected an error, got driver %s", def.Name)
+	}
+}
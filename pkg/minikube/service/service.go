@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"text/template"
@@ -65,6 +66,21 @@ func (k *K8sClientGetter) GetCoreClient() (corev1.CoreV1Interface, error) {
 }
 
 func (*K8sClientGetter) GetClientset() (*kubernetes.Clientset, error) {
+	clientConfig, err := GetRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating new client from kubeConfig.ClientConfig()")
+	}
+
+	return client, nil
+}
+
+// GetRESTConfig returns the client config for the current profile's cluster
+// from the kubeconfig
+func GetRESTConfig() (*rest.Config, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	profile := viper.GetString(config.MachineProfile)
 	configOverrides := &clientcmd.ConfigOverrides{
@@ -78,12 +94,7 @@ func (*K8sClientGetter) GetClientset() (*kubernetes.Clientset, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Error creating kubeConfig: %s", err)
 	}
-	client, err := kubernetes.NewForConfig(clientConfig)
-	if err != nil {
-		return nil, errors.Wrap(err, "Error creating new client from kubeConfig.ClientConfig()")
-	}
-
-	return client, nil
+	return clientConfig, nil
 }
 
 type ServiceURL struct {
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package support collects the diagnostics of a cluster into a single archive
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// ErrorsFile lists the items of a bundle which could not be collected
+const ErrorsFile = "errors.txt"
+
+// Item is a file of the support bundle
+type Item struct {
+	// Name is the path of the file in the bundle
+	Name string
+	// Collect returns the contents of the file. Items which return an error
+	// are still written with the data they did collect.
+	Collect func() ([]byte, error)
+	// JSON items have the values of their secret looking keys redacted
+	JSON bool
+}
+
+// WriteBundle collects the items and writes them, redacted, to a gzipped
+// tarball under the dir directory. Collection errors don't fail the bundle,
+// they are listed in its ErrorsFile instead.
+func WriteBundle(w io.Writer, dir string, items []Item) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	var failures bytes.Buffer
+	for _, item := range items {
+		glog.Infof("Collecting %s", item.Name)
+		data, err := item.Collect()
+		if err != nil {
+			fmt.Fprintf(&failures, "%s: %s\n", item.Name, err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+		data, err = redact(item, data)
+		if err != nil {
+			fmt.Fprintf(&failures, "%s: %s\n", item.Name, err)
+			continue
+		}
+		if err := writeFile(tw, path.Join(dir, item.Name), data, now); err != nil {
+			return errors.Wrapf(err, "writing %s", item.Name)
+		}
+	}
+	if failures.Len() > 0 {
+		if err := writeFile(tw, path.Join(dir, ErrorsFile), failures.Bytes(), now); err != nil {
+			return errors.Wrap(err, "writing errors")
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "closing tarball")
+	}
+	return errors.Wrap(gz.Close(), "closing gzip")
+}
+
+func redact(item Item, data []byte) ([]byte, error) {
+	if !item.JSON {
+		return []byte(RedactText(string(data))), nil
+	}
+	b, err := RedactJSON(data)
+	if err != nil {
+		// Don't leak secrets from a file which can't be parsed
+		return nil, errors.Wrap(err, "redacting")
+	}
+	return b, nil
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteBundle(t *testing.T) {
+	items := []Item{
+		{
+			Name:    "versions.txt",
+			Collect: func() ([]byte, error) { return []byte("minikube: v0.26.0\n"), nil },
+		},
+		{
+			Name:    "config.json",
+			Collect: func() ([]byte, error) { return []byte(`{"Password": "hunter2"}`), nil },
+			JSON:    true,
+		},
+		{
+			Name:    "partial.txt",
+			Collect: func() ([]byte, error) { return []byte("TOKEN=abc\n"), errors.New("exit status 1") },
+		},
+		{
+			Name:    "vm/logs.txt",
+			Collect: func() ([]byte, error) { return nil, errors.New("the VM is not running") },
+		},
+		{
+			Name:    "broken.json",
+			Collect: func() ([]byte, error) { return []byte(`{"Password": `), nil },
+			JSON:    true,
+		},
+	}
+
+	var b bytes.Buffer
+	if err := WriteBundle(&b, "bundle", items); err != nil {
+		t.Fatalf("Error writing bundle: %s", err)
+	}
+
+	gz, err := gzip.NewReader(&b)
+	if err != nil {
+		t.Fatalf("Error reading gzip: %s", err)
+	}
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error reading tarball: %s", err)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("Error reading %s: %s", hdr.Name, err)
+		}
+		files[hdr.Name] = string(data)
+	}
+
+	errs := files["bundle/errors.txt"]
+	delete(files, "bundle/errors.txt")
+	expected := map[string]string{
+		"bundle/versions.txt": "minikube: v0.26.0\n",
+		"bundle/config.json":  "{\n    \"Password\": \"REDACTED\"\n}",
+		"bundle/partial.txt":  "TOKEN=REDACTED\n",
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected files %+v, got %+v", expected, files)
+	}
+	for _, e := range []string{"partial.txt: exit status 1", "vm/logs.txt: the VM is not running", "broken.json: redacting"} {
+		if !strings.Contains(errs, e) {
+			t.Errorf("Expected errors to contain %q, got:\n%s", e, errs)
+		}
+	}
+}
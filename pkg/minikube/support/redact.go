@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package support
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Redacted replaces the secrets removed from the bundle
+const Redacted = "REDACTED"
+
+// secretPattern matches the names of config keys and environment variables
+// holding secrets
+const secretPattern = `password|passwd|secret|token|credential|access_?key|api_?key|private_?key`
+
+var secretKey = regexp.MustCompile(`(?i)` + secretPattern)
+
+// secretParents are config keys whose values are all secrets
+var secretParents = map[string]bool{
+	"registry-creds": true,
+}
+
+var (
+	// privateKeys matches PEM encoded private keys
+	privateKeys = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+	// urlPasswords matches the password of a URL, e.g. of a proxy
+	urlPasswords = regexp.MustCompile(`(\w+://[^:/@\s]+:)[^@\s]+@`)
+	// envSecrets matches KEY=VALUE pairs where the key looks like a secret
+	envSecrets = regexp.MustCompile(`(?i)(\b[\w.-]*(?:` + secretPattern + `)[\w.-]*=)[^\s"',]+`)
+	// bearerTokens matches authorization headers
+	bearerTokens = regexp.MustCompile(`(?i)(bearer\s+)[\w.~+/=-]+`)
+)
+
+// RedactText removes private keys, URL passwords, bearer tokens and secret
+// looking KEY=VALUE pairs from free form text such as logs.
+func RedactText(s string) string {
+	s = privateKeys.ReplaceAllString(s, Redacted)
+	s = urlPasswords.ReplaceAllString(s, "${1}"+Redacted+"@")
+	s = envSecrets.ReplaceAllString(s, "${1}"+Redacted)
+	return bearerTokens.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// RedactJSON removes the values of secret looking keys from a JSON document,
+// and the secrets in all of its strings. The result is indented.
+func RedactJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, errors.Wrap(err, "decoding json")
+	}
+	b, err := json.MarshalIndent(redactValue(v, false), "", "    ")
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding json")
+	}
+	return b, nil
+}
+
+// isSecretKey returns whether the values of a config key are secrets. Paths
+// to key files, e.g. libmachine's ClientKeyPath, are kept.
+func isSecretKey(k string) bool {
+	if secretParents[k] {
+		return true
+	}
+	return secretKey.MatchString(k) && !strings.HasSuffix(k, "Path")
+}
+
+func redactValue(v interface{}, secret bool) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			t[k] = redactValue(child, secret || isSecretKey(k))
+		}
+		return t
+	case []interface{}:
+		for i, child := range t {
+			t[i] = redactValue(child, secret)
+		}
+		return t
+	case string:
+		if secret && t != "" {
+			return Redacted
+		}
+		return RedactText(t)
+	case nil:
+		return nil
+	default:
+		if secret {
+			return Redacted
+		}
+		return t
+	}
+}
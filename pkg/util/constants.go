@@ -47,23 +47,29 @@ var DefaultAdmissionControllers = []string{
 
 // GetServiceClusterIP returns the first IP of the ServiceCIDR
 func GetServiceClusterIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	_, network, err := net.ParseCIDR(serviceCIDR)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing default service cidr")
+		return nil, errors.Wrap(err, "parsing service cidr")
+	}
+	ip := network.IP.To4()
+	if ip == nil {
+		return nil, errors.Errorf("service cidr %s is not an IPv4 network", serviceCIDR)
 	}
-	ip = ip.To4()
 	ip[3]++
 	return ip, nil
 }
 
-// GetDNSIP returns x.x.x.10 of the service CIDR
+// GetDNSIP returns the 10th IP of the service CIDR
 func GetDNSIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	_, network, err := net.ParseCIDR(serviceCIDR)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing default service cidr")
+		return nil, errors.Wrap(err, "parsing service cidr")
+	}
+	ip := network.IP.To4()
+	if ip == nil {
+		return nil, errors.Errorf("service cidr %s is not an IPv4 network", serviceCIDR)
 	}
-	ip = ip.To4()
-	ip[3] = 10
+	ip[3] += 10
 	return ip, nil
 }
 
@@ -0,0 +1,52 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "testing"
+
+func TestServiceIPs(t *testing.T) {
+	var tests = []struct {
+		serviceCIDR string
+		clusterIP   string
+		dnsIP       string
+	}{
+		{DefaultServiceCIDR, "10.96.0.1", "10.96.0.10"},
+		{"172.30.0.0/16", "172.30.0.1", "172.30.0.10"},
+		// The network address is used, rather than the address given
+		{"10.0.0.20/28", "10.0.0.17", "10.0.0.26"},
+	}
+	for _, test := range tests {
+		ip, err := GetServiceClusterIP(test.serviceCIDR)
+		if err != nil {
+			t.Fatalf("Error getting service cluster ip of %s: %s", test.serviceCIDR, err)
+		}
+		if ip.String() != test.clusterIP {
+			t.Errorf("Expected service cluster ip %s for %s, got %s", test.clusterIP, test.serviceCIDR, ip)
+		}
+		dnsIP, err := GetDNSIP(test.serviceCIDR)
+		if err != nil {
+			t.Fatalf("Error getting dns ip of %s: %s", test.serviceCIDR, err)
+		}
+		if dnsIP.String() != test.dnsIP {
+			t.Errorf("Expected dns ip %s for %s, got %s", test.dnsIP, test.serviceCIDR, dnsIP)
+		}
+	}
+
+	if _, err := GetDNSIP("fd00::/108"); err == nil {
+		t.Errorf("Expected an error for an IPv6 service cidr")
+	}
+}
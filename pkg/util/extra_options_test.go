@@ -78,3 +78,27 @@ func TestValidFlags(t *testing.T) {
 		}
 	}
 }
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"kubelet", "apiserver", "controller-manager", "scheduler"}
+	for _, tc := range []struct {
+		s        string
+		expected string
+		ok       bool
+	}{
+		{s: "apiservr", expected: "apiserver", ok: true},
+		{s: "controller-manger", expected: "controller-manager", ok: true},
+		{s: "kubelet", expected: "kubelet", ok: true},
+		{s: "Kubelet", expected: "kubelet", ok: true},
+		{s: "etcd", ok: false},
+		{s: "", ok: false},
+	} {
+		actual, ok := Suggest(tc.s, candidates)
+		if actual != tc.expected || ok != tc.ok {
+			t.Errorf("Suggest(%q): expected %q, %t, got %q, %t", tc.s, tc.expected, tc.ok, actual, ok)
+		}
+	}
+	if _, ok := Suggest("kubelet", nil); ok {
+		t.Error("Expected no suggestion without candidates")
+	}
+}
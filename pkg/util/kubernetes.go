@@ -139,6 +139,23 @@ func WaitForPodsWithLabelRunning(c kubernetes.Interface, ns string, label labels
 	})
 }
 
+// WaitForNodeReady waits up to timeout for the node to report the Ready condition.
+func WaitForNodeReady(c kubernetes.Interface, name string, timeout time.Duration) error {
+	return wait.PollImmediate(constants.APICallRetryInterval, timeout, func() (bool, error) {
+		node, err := c.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+		if err != nil {
+			glog.Infof("error getting node %s [%v]\n", name, err)
+			return false, nil
+		}
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == v1.NodeReady {
+				return cond.Status == v1.ConditionTrue, nil
+			}
+		}
+		return false, nil
+	})
+}
+
 // WaitForRCToStabilize waits till the RC has a matching generation/replica count between spec and status.
 func WaitForRCToStabilize(c kubernetes.Interface, ns, name string, timeout time.Duration) error {
 	options := metav1.ListOptions{FieldSelector: fields.Set{
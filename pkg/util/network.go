@@ -0,0 +1,221 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const procNetRoute = "/proc/net/route"
+
+// PrivateNetwork describes an IPv4 network shared between the host and a VM,
+// with the host acting as gateway and DHCP server.
+type PrivateNetwork struct {
+	CIDR *net.IPNet
+	// Gateway is the first address in the network, assigned to the host
+	Gateway net.IP
+	Netmask net.IP
+	// DHCPStart and DHCPEnd bound the addresses leased to VMs
+	DHCPStart net.IP
+	DHCPEnd   net.IP
+}
+
+// ParsePrivateNetwork parses an IPv4 CIDR, such as 192.168.39.0/24, into a
+// PrivateNetwork.
+func ParsePrivateNetwork(cidr string) (*PrivateNetwork, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing network cidr %s", cidr)
+	}
+	network := ipNet.IP.To4()
+	if network == nil {
+		return nil, errors.Errorf("network cidr %s is not an IPv4 network", cidr)
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones < 2 {
+		return nil, errors.Errorf("network cidr %s is too small, it must have room for at least two hosts", cidr)
+	}
+
+	start := binary.BigEndian.Uint32(network)
+	broadcast := start | ^binary.BigEndian.Uint32(net.IP(ipNet.Mask).To4())
+	return &PrivateNetwork{
+		CIDR:      ipNet,
+		Gateway:   uint32ToIP(start + 1),
+		Netmask:   net.IP(ipNet.Mask).To4(),
+		DHCPStart: uint32ToIP(start + 2),
+		DHCPEnd:   uint32ToIP(broadcast - 1),
+	}, nil
+}
+
+func uint32ToIP(n uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, n)
+	return ip
+}
+
+// Route is an entry in the host routing table.
+type Route struct {
+	Interface   string
+	Destination *net.IPNet
+}
+
+func (r Route) String() string {
+	return fmt.Sprintf("%s via %s", r.Destination, r.Interface)
+}
+
+// HostRoutes returns the host routing table, excluding default routes. Hosts
+// without /proc/net/route return no routes.
+func HostRoutes() ([]Route, error) {
+	f, err := os.Open(procNetRoute)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "reading routes")
+	}
+	defer f.Close()
+	routes, err := parseRoutes(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", procNetRoute)
+	}
+	var nonDefault []Route
+	for _, r := range routes {
+		if ones, _ := r.Destination.Mask.Size(); ones != 0 {
+			nonDefault = append(nonDefault, r)
+		}
+	}
+	return nonDefault, nil
+}
+
+// FindOverlappingRoute returns a host route whose destination overlaps
+// network, or nil if there is none. Routes through libvirt bridges are
+// ignored, since libvirt refuses to create overlapping networks itself.
+func FindOverlappingRoute(network *net.IPNet) (*Route, error) {
+	routes, err := HostRoutes()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range routes {
+		if strings.HasPrefix(r.Interface, "virbr") {
+			continue
+		}
+		if NetworksOverlap(network, r.Destination) {
+			route := r
+			return &route, nil
+		}
+	}
+	return nil, nil
+}
+
+// FreePrivateNetwork returns the first /24 network, starting at first and
+// counting up within its /16, that doesn't overlap any of the used networks.
+func FreePrivateNetwork(first string, used []*net.IPNet) (string, error) {
+	ip := net.ParseIP(first).To4()
+	if ip == nil {
+		return "", errors.Errorf("invalid IPv4 address %s", first)
+	}
+	for third := int(ip[2]); third <= 255; third++ {
+		candidate := &net.IPNet{IP: net.IPv4(ip[0], ip[1], byte(third), 0).To4(), Mask: net.CIDRMask(24, 32)}
+		free := true
+		for _, u := range used {
+			if NetworksOverlap(candidate, u) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return candidate.String(), nil
+		}
+	}
+	return "", errors.Errorf("no free /24 network found after %s", first)
+}
+
+// ValidateClusterNetworks checks the service and pod networks of a cluster.
+// The pod network is optional.
+func ValidateClusterNetworks(serviceCIDR, podCIDR string) error {
+	_, service, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		return errors.Wrap(err, "parsing service cidr")
+	}
+	if service.IP.To4() == nil {
+		return errors.Errorf("service cidr %s is not an IPv4 network", serviceCIDR)
+	}
+	if ones, bits := service.Mask.Size(); bits-ones < 4 {
+		return errors.Errorf("service cidr %s is too small to hold the apiserver and dns service IPs", serviceCIDR)
+	}
+	if podCIDR == "" {
+		return nil
+	}
+	_, pod, err := net.ParseCIDR(podCIDR)
+	if err != nil {
+		return errors.Wrap(err, "parsing pod cidr")
+	}
+	if NetworksOverlap(service, pod) {
+		return errors.Errorf("service cidr %s overlaps pod cidr %s", serviceCIDR, podCIDR)
+	}
+	return nil
+}
+
+// NetworksOverlap returns whether two networks share any address
+func NetworksOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// parseRoutes parses the routing table format of /proc/net/route, where
+// addresses are hex encoded in host byte order.
+func parseRoutes(r io.Reader) ([]Route, error) {
+	var routes []Route
+	scanner := bufio.NewScanner(r)
+	// Skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		dest, err := parseProcIP(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		mask, err := parseProcIP(fields[7])
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, Route{
+			Interface:   fields[0],
+			Destination: &net.IPNet{IP: dest, Mask: net.IPMask(mask)},
+		})
+	}
+	return routes, scanner.Err()
+}
+
+func parseProcIP(s string) (net.IP, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != net.IPv4len {
+		return nil, errors.Errorf("invalid address %q", s)
+	}
+	return uint32ToIP(binary.LittleEndian.Uint32(b)), nil
+}
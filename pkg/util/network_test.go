@@ -0,0 +1,190 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParsePrivateNetwork(t *testing.T) {
+	var tests = []struct {
+		cidr      string
+		gateway   string
+		netmask   string
+		dhcpStart string
+		dhcpEnd   string
+		shouldErr bool
+	}{
+		{
+			cidr:      "192.168.39.0/24",
+			gateway:   "192.168.39.1",
+			netmask:   "255.255.255.0",
+			dhcpStart: "192.168.39.2",
+			dhcpEnd:   "192.168.39.254",
+		},
+		{
+			cidr:      "10.200.0.0/16",
+			gateway:   "10.200.0.1",
+			netmask:   "255.255.0.0",
+			dhcpStart: "10.200.0.2",
+			dhcpEnd:   "10.200.255.254",
+		},
+		{
+			// The host bits are ignored
+			cidr:      "172.20.5.7/28",
+			gateway:   "172.20.5.1",
+			netmask:   "255.255.255.240",
+			dhcpStart: "172.20.5.2",
+			dhcpEnd:   "172.20.5.14",
+		},
+		{
+			cidr:      "192.168.39.0/31",
+			shouldErr: true,
+		},
+		{
+			cidr:      "fd00::/64",
+			shouldErr: true,
+		},
+		{
+			cidr:      "192.168.39.0",
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cidr, func(t *testing.T) {
+			n, err := ParsePrivateNetwork(test.cidr)
+			if test.shouldErr {
+				if err == nil {
+					t.Errorf("Expected error parsing %s", test.cidr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error parsing %s: %s", test.cidr, err)
+			}
+			for _, c := range []struct {
+				name     string
+				got      net.IP
+				expected string
+			}{
+				{"gateway", n.Gateway, test.gateway},
+				{"netmask", n.Netmask, test.netmask},
+				{"dhcp start", n.DHCPStart, test.dhcpStart},
+				{"dhcp end", n.DHCPEnd, test.dhcpEnd},
+			} {
+				if c.got.String() != c.expected {
+					t.Errorf("Expected %s %s, got %s", c.name, c.expected, c.got)
+				}
+			}
+		})
+	}
+}
+
+const testRoutes = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	0102A8C0	0003	0	0	100	00000000	0	0	0
+eth0	0002A8C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+tun0	0000000A	00000000	0001	0	0	0	0000FFFF	0	0	0
+virbr1	0027A8C0	00000000	0001	0	0	0	00FFFFFF	0	0	0
+`
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := parseRoutes(strings.NewReader(testRoutes))
+	if err != nil {
+		t.Fatalf("Error parsing routes: %s", err)
+	}
+	expected := []string{
+		"0.0.0.0/0 via eth0",
+		"192.168.2.0/24 via eth0",
+		"10.0.0.0/16 via tun0",
+		"192.168.39.0/24 via virbr1",
+	}
+	if len(routes) != len(expected) {
+		t.Fatalf("Expected %d routes, got %d", len(expected), len(routes))
+	}
+	for i, r := range routes {
+		if r.String() != expected[i] {
+			t.Errorf("Expected route %s, got %s", expected[i], r)
+		}
+	}
+}
+
+func TestNetworksOverlap(t *testing.T) {
+	var tests = []struct {
+		a, b     string
+		expected bool
+	}{
+		{"192.168.39.0/24", "192.168.39.0/24", true},
+		{"192.168.39.0/24", "192.168.0.0/16", true},
+		{"10.0.0.0/8", "10.96.0.0/12", true},
+		{"192.168.39.0/24", "192.168.40.0/24", false},
+		{"172.16.0.0/12", "192.168.0.0/16", false},
+	}
+	for _, test := range tests {
+		_, a, _ := net.ParseCIDR(test.a)
+		_, b, _ := net.ParseCIDR(test.b)
+		if got := NetworksOverlap(a, b); got != test.expected {
+			t.Errorf("Expected overlap of %s and %s to be %t, got %t", test.a, test.b, test.expected, got)
+		}
+	}
+}
+
+func TestFreePrivateNetwork(t *testing.T) {
+	var used []*net.IPNet
+	for _, cidr := range []string{"192.168.39.0/24", "192.168.40.0/23", "10.0.0.0/8"} {
+		_, n, _ := net.ParseCIDR(cidr)
+		used = append(used, n)
+	}
+
+	cidr, err := FreePrivateNetwork("192.168.39.0", used)
+	if err != nil {
+		t.Fatalf("Error finding free network: %s", err)
+	}
+	if cidr != "192.168.42.0/24" {
+		t.Errorf("Expected 192.168.42.0/24, got %s", cidr)
+	}
+
+	if _, err := FreePrivateNetwork("10.1.0.0", used); err == nil {
+		t.Errorf("Expected error when no network is free")
+	}
+}
+
+func TestValidateClusterNetworks(t *testing.T) {
+	var tests = []struct {
+		serviceCIDR string
+		podCIDR     string
+		shouldErr   bool
+	}{
+		{"10.96.0.0/12", "", false},
+		{"10.96.0.0/12", "10.244.0.0/16", false},
+		{"172.30.0.0/16", "10.244.0.0/16", false},
+		{"10.96.0.0/12", "10.100.0.0/16", true},
+		{"10.0.0.0/8", "10.244.0.0/16", true},
+		{"not a cidr", "", true},
+		{"10.96.0.0/12", "not a cidr", true},
+		{"fd00::/108", "", true},
+		{"10.96.0.0/30", "", true},
+	}
+	for _, test := range tests {
+		err := ValidateClusterNetworks(test.serviceCIDR, test.podCIDR)
+		if (err != nil) != test.shouldErr {
+			t.Errorf("Expected error %t for service cidr %s and pod cidr %s, got %v", test.shouldErr, test.serviceCIDR, test.podCIDR, err)
+		}
+	}
+}
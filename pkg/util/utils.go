@@ -207,24 +207,51 @@ func ChownR(path string, uid, gid int) error {
 	})
 }
 
+// SudoUser returns the regular user that ran minikube through sudo, or nil
+// if minikube wasn't run that way.
+func SudoUser() (*user.User, error) {
+	username := os.Getenv("SUDO_USER")
+	if os.Geteuid() != 0 || username == "" || username == "root" {
+		return nil, nil
+	}
+	usr, err := user.Lookup(username)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error looking up user")
+	}
+	return usr, nil
+}
+
+// MaybeChownDirRecursiveToMinikubeUser gives dir back to the user that ran
+// minikube through sudo. Only directories in that user's home directory are
+// changed, so that system directories keep their owner.
 func MaybeChownDirRecursiveToMinikubeUser(dir string) error {
-	if os.Getenv("CHANGE_MINIKUBE_NONE_USER") != "" && os.Getenv("SUDO_USER") != "" {
-		username := os.Getenv("SUDO_USER")
-		usr, err := user.Lookup(username)
-		if err != nil {
-			return errors.Wrap(err, "Error looking up user")
-		}
-		uid, err := strconv.Atoi(usr.Uid)
-		if err != nil {
-			return errors.Wrapf(err, "Error parsing uid for user: %s", username)
-		}
-		gid, err := strconv.Atoi(usr.Gid)
-		if err != nil {
-			return errors.Wrapf(err, "Error parsing gid for user: %s", username)
-		}
-		if err := ChownR(dir, uid, gid); err != nil {
-			return errors.Wrapf(err, "Error changing ownership for: %s", dir)
-		}
+	usr, err := SudoUser()
+	if err != nil || usr == nil {
+		return err
+	}
+	if !inDir(dir, usr.HomeDir) {
+		glog.Infof("Not changing ownership of %s, it is outside of %s", dir, usr.HomeDir)
+		return nil
+	}
+	uid, err := strconv.Atoi(usr.Uid)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing uid for user: %s", usr.Username)
+	}
+	gid, err := strconv.Atoi(usr.Gid)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing gid for user: %s", usr.Username)
+	}
+	if err := ChownR(dir, uid, gid); err != nil {
+		return errors.Wrapf(err, "Error changing ownership for: %s", dir)
 	}
 	return nil
 }
+
+// inDir returns whether path is dir or inside of it
+func inDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -182,3 +182,23 @@ func TestGetBinaryDownloadURL(t *testing.T) {
 	}
 
 }
+
+func TestInDir(t *testing.T) {
+	var tests = []struct {
+		path     string
+		dir      string
+		expected bool
+	}{
+		{"/home/user/.minikube", "/home/user", true},
+		{"/home/user", "/home/user", true},
+		{"/home/user/../other/.kube", "/home/user", false},
+		{"/home/username/.kube", "/home/user", false},
+		{"/etc/kubernetes", "/home/user", false},
+		{"/home/user/..minikube", "/home/user", true},
+	}
+	for _, test := range tests {
+		if got := inDir(test.path, test.dir); got != test.expected {
+			t.Errorf("Expected inDir(%s, %s) to be %t, got %t", test.path, test.dir, test.expected, got)
+		}
+	}
+}